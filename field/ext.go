@@ -0,0 +1,131 @@
+package field
+
+import (
+	"errors"
+	"fmt"
+
+	fr "simplex/fraction"
+)
+
+// ErrUndefined is the panic value of Ext operations with no defined result,
+// such as +∞ + -∞ or 0 * ∞
+var ErrUndefined = errors.New("field: undefined operation on infinity")
+
+// Ext extends T with +∞ and -∞, so that ratio tests and bounds can say
+// "unbounded" explicitly. Ext[fraction.Fraction] is the extended rationals.
+// The zero value is the finite value 0.
+type Ext[T Element[T]] struct {
+	inf int // +1 for +∞, -1 for -∞, 0 when finite
+	v   T
+}
+
+// Finite returns v as an extended value
+func Finite[T Element[T]](v T) Ext[T] {
+	return Ext[T]{v: v}
+}
+
+// Inf returns +∞ if sign >= 0 and -∞ otherwise
+func Inf[T Element[T]](sign int) Ext[T] {
+	if sign < 0 {
+		return Ext[T]{inf: -1}
+	}
+	return Ext[T]{inf: 1}
+}
+
+func (x Ext[T]) IsInf() bool { return x.inf != 0 }
+
+// Value returns the finite value of x, and false if x is infinite
+func (x Ext[T]) Value() (T, bool) {
+	return x.v, x.inf == 0
+}
+
+func (x Ext[T]) Sign() int {
+	if x.inf != 0 {
+		return x.inf
+	}
+	return x.v.Sign()
+}
+
+func (x Ext[T]) Cmp(y Ext[T]) int {
+	switch {
+	case x.inf < y.inf:
+		return -1
+	case x.inf > y.inf:
+		return 1
+	case x.inf != 0:
+		return 0
+	}
+	return x.v.Cmp(y.v)
+}
+
+func (x Ext[T]) Less(y Ext[T]) bool { return x.Cmp(y) < 0 }
+
+func (x Ext[T]) Neg() Ext[T] {
+	return Ext[T]{inf: -x.inf, v: x.v.Neg()}
+}
+
+// Add panics with ErrUndefined for +∞ + -∞
+func (x Ext[T]) Add(y Ext[T]) Ext[T] {
+	switch {
+	case x.inf != 0 && y.inf != 0 && x.inf != y.inf:
+		panic(ErrUndefined)
+	case x.inf != 0:
+		return x
+	case y.inf != 0:
+		return y
+	}
+	return Finite(x.v.Add(y.v))
+}
+
+// Sub panics with ErrUndefined for ∞ - ∞
+func (x Ext[T]) Sub(y Ext[T]) Ext[T] {
+	return x.Add(y.Neg())
+}
+
+// Mul panics with ErrUndefined for 0 * ∞
+func (x Ext[T]) Mul(y Ext[T]) Ext[T] {
+	if x.inf == 0 && y.inf == 0 {
+		return Finite(x.v.Mul(y.v))
+	}
+	if x.Sign() == 0 || y.Sign() == 0 {
+		panic(ErrUndefined)
+	}
+	return Inf[T](x.Sign() * y.Sign())
+}
+
+// Div panics with ErrUndefined for ∞ / ∞ and with
+// fraction.ErrDivisionByZero for a zero divisor
+func (x Ext[T]) Div(y Ext[T]) Ext[T] {
+	switch {
+	case x.inf != 0 && y.inf != 0:
+		panic(ErrUndefined)
+	case y.inf != 0:
+		var zero T
+		return Finite(zero)
+	case y.v.IsZero():
+		panic(fr.ErrDivisionByZero)
+	case x.inf != 0:
+		return Inf[T](x.inf * y.v.Sign())
+	}
+	return Finite(x.v.Div(y.v))
+}
+
+func (x Ext[T]) String() string {
+	switch x.inf {
+	case 1:
+		return "∞"
+	case -1:
+		return "-∞"
+	}
+	return x.v.String()
+}
+
+// Format formats finite values as T does; infinities print as ∞ and -∞ with
+// the same width and flags
+func (x Ext[T]) Format(f fmt.State, verb rune) {
+	if x.inf == 0 {
+		x.v.Format(f, verb)
+		return
+	}
+	fr.Pad(f, x.String())
+}
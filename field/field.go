@@ -0,0 +1,46 @@
+// Package field defines the number types the simplex tableau can run on
+package field
+
+import (
+	"fmt"
+
+	fr "simplex/fraction"
+)
+
+// Element is implemented by the value types a tableau can be built from.
+// Operations never modify their receiver, and the zero value of T must be 0.
+type Element[T any] interface {
+	Add(T) T
+	Sub(T) T
+	Mul(T) T
+	Div(T) T
+	Neg() T
+	Cmp(T) int
+	Sign() int
+	IsZero() bool
+
+	// FromFraction converts an exact value into T; it ignores its receiver
+	FromFraction(fr.Fraction) T
+
+	// Elements print through fmt; "% -10v" must give a space before
+	// non-negative values and left-justify, as fraction.Fraction does
+	fmt.Stringer
+	fmt.Formatter
+}
+
+// From converts f into the element type T
+func From[T Element[T]](f fr.Fraction) T {
+	var zero T
+	return zero.FromFraction(f)
+}
+
+// One returns the multiplicative identity of T
+func One[T Element[T]]() T {
+	return From[T](fr.Fraction{N: 1, D: 1})
+}
+
+// Checker is implemented by elements that can hold an invalid value, such as
+// a fraction with a zero denominator or a NaN float
+type Checker interface {
+	Err() error
+}
@@ -0,0 +1,93 @@
+package field
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	fr "simplex/fraction"
+)
+
+// Epsilon is the tolerance Float uses when comparing values. Two floats are
+// equal when they differ by at most Epsilon, scaled by their magnitude once
+// that exceeds 1.
+var Epsilon = 1e-9
+
+// ErrNotFinite is returned by Float.Err for NaN and infinite values
+var ErrNotFinite = fr.ErrNotFinite
+
+// Float is an approximate element backed by float64
+type Float float64
+
+func (a Float) Add(b Float) Float { return a + b }
+func (a Float) Sub(b Float) Float { return a - b }
+func (a Float) Mul(b Float) Float { return a * b }
+func (a Float) Div(b Float) Float { return a / b }
+func (a Float) Neg() Float        { return -a }
+
+func (a Float) Cmp(b Float) int {
+	scale := math.Max(1, math.Max(math.Abs(float64(a)), math.Abs(float64(b))))
+	switch d := float64(a - b); {
+	case math.Abs(d) <= Epsilon*scale:
+		return 0
+	case d < 0:
+		return -1
+	}
+	return 1
+}
+
+func (a Float) Err() error {
+	if math.IsNaN(float64(a)) || math.IsInf(float64(a), 0) {
+		return ErrNotFinite
+	}
+	return nil
+}
+
+// Sign is a.Cmp(0), spelled out because pivoting calls it for every entry
+func (a Float) Sign() int {
+	switch {
+	case float64(a) > Epsilon:
+		return 1
+	case float64(a) < -Epsilon:
+		return -1
+	}
+	return 0
+}
+
+func (a Float) IsZero() bool { return a.Sign() == 0 }
+
+func (Float) FromFraction(f fr.Fraction) Float {
+	x, _ := f.Rat().Float64()
+	return Float(x)
+}
+
+func (a Float) String() string {
+	if a.IsZero() {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(a), 'g', 6, 64)
+}
+
+// Fraction returns the simplest fraction within Epsilon of a, which recovers
+// values like 1/3 from the rounding noise of a float solve
+func (a Float) Fraction() (fr.Fraction, error) {
+	return fr.Approximate(float64(a), Epsilon*math.Max(1, math.Abs(float64(a))))
+}
+
+// Format prints %v and %s through String, honouring width and the '-', '+'
+// and ' ' flags. %r prints the value as Fraction would and accepts the same
+// flags; other verbs format the underlying float64
+func (a Float) Format(f fmt.State, verb rune) {
+	if verb == 'r' {
+		if x, err := a.Fraction(); err == nil {
+			x.Format(f, 'v')
+			return
+		}
+		verb = 'v'
+	}
+	if verb != 'v' && verb != 's' {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), float64(a))
+		return
+	}
+	fr.Pad(f, a.String())
+}
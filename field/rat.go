@@ -0,0 +1,53 @@
+package field
+
+import (
+	"fmt"
+	"math/big"
+
+	fr "simplex/fraction"
+)
+
+// Rat is an exact element backed by *big.Rat. The zero value is 0.
+type Rat struct {
+	r *big.Rat
+}
+
+// NewRat wraps a copy of r
+func NewRat(r *big.Rat) Rat {
+	return Rat{r: new(big.Rat).Set(r)}
+}
+
+// Rat returns the value as a newly allocated *big.Rat
+func (a Rat) Rat() *big.Rat {
+	return new(big.Rat).Set(a.val())
+}
+
+func (a Rat) val() *big.Rat {
+	if a.r == nil {
+		return new(big.Rat)
+	}
+	return a.r
+}
+
+func (a Rat) Add(b Rat) Rat { return Rat{r: new(big.Rat).Add(a.val(), b.val())} }
+func (a Rat) Sub(b Rat) Rat { return Rat{r: new(big.Rat).Sub(a.val(), b.val())} }
+func (a Rat) Mul(b Rat) Rat { return Rat{r: new(big.Rat).Mul(a.val(), b.val())} }
+func (a Rat) Div(b Rat) Rat { return Rat{r: new(big.Rat).Quo(a.val(), b.val())} }
+func (a Rat) Neg() Rat      { return Rat{r: new(big.Rat).Neg(a.val())} }
+
+func (a Rat) Cmp(b Rat) int { return a.val().Cmp(b.val()) }
+func (a Rat) Sign() int     { return a.val().Sign() }
+func (a Rat) IsZero() bool  { return a.Sign() == 0 }
+
+func (Rat) FromFraction(f fr.Fraction) Rat {
+	return Rat{r: f.Rat()}
+}
+
+func (a Rat) String() string {
+	return a.val().RatString()
+}
+
+// Format accepts the same verbs as fraction.Fraction
+func (a Rat) Format(f fmt.State, verb rune) {
+	fr.FromRat(a.val()).Format(f, verb)
+}
@@ -0,0 +1,106 @@
+package fraction
+
+import (
+  "errors"
+  "math"
+  "math/big"
+)
+
+var ErrNotFinite = errors.New("fraction: value is not finite")
+
+// FromFloat returns the fraction closest to x whose denominator is at most
+// maxDen, found from the continued fraction expansion of x. A maxDen below 1
+// is treated as 1.
+func FromFloat(x float64, maxDen int) (Fraction, error) {
+  r, err := exact(x)
+  if err != nil {
+    return Fraction{}, err
+  }
+  limit := big.NewInt(int64(max(maxDen, 1)))
+  if r.Denom().Cmp(limit) <= 0 {
+    return FromRat(r), nil
+  }
+
+  // Convergents p1/q1 of x, with p0/q0 the one before
+  p0, q0, p1, q1 := big.NewInt(0), big.NewInt(1), big.NewInt(1), big.NewInt(0)
+  n, d := new(big.Int).Set(r.Num()), new(big.Int).Set(r.Denom())
+  a, t := new(big.Int), new(big.Int)
+  for {
+    a.Div(n, d)
+    q2 := new(big.Int).Add(q0, t.Mul(a, q1))
+    if q2.Cmp(limit) > 0 {
+      break
+    }
+    p0, q0, p1, q1 = p1, q1, new(big.Int).Add(p0, t.Mul(a, p1)), q2
+    n, d = d, new(big.Int).Sub(n, t.Mul(a, d))
+  }
+
+  // The best approximation is either the last convergent or the largest
+  // semiconvergent that still fits under the limit
+  k := new(big.Int).Div(new(big.Int).Sub(limit, q0), q1)
+  semi := new(big.Rat).SetFrac(
+    new(big.Int).Add(p0, new(big.Int).Mul(k, p1)),
+    new(big.Int).Add(q0, new(big.Int).Mul(k, q1)))
+  conv := new(big.Rat).SetFrac(p1, q1)
+  if distance(conv, r).Cmp(distance(semi, r)) <= 0 {
+    return FromRat(conv), nil
+  }
+  return FromRat(semi), nil
+}
+
+// Approximate returns the simplest fraction, the one with the smallest
+// denominator, within tol of x.
+func Approximate(x, tol float64) (Fraction, error) {
+  r, err := exact(x)
+  if err != nil {
+    return Fraction{}, err
+  }
+  t, err := exact(math.Abs(tol))
+  if err != nil {
+    return Fraction{}, err
+  }
+  lo := new(big.Rat).Sub(r, t)
+  hi := new(big.Rat).Add(r, t)
+  return FromRat(simplest(lo, hi)), nil
+}
+
+// simplest returns the rational with the smallest denominator in [lo, hi],
+// walking the Stern–Brocot tree one continued fraction term at a time.
+func simplest(lo, hi *big.Rat) *big.Rat {
+  switch {
+  case lo.Sign() <= 0 && hi.Sign() >= 0:
+    return new(big.Rat)
+  case hi.Sign() < 0:
+    r := simplest(new(big.Rat).Neg(hi), new(big.Rat).Neg(lo))
+    return r.Neg(r)
+  }
+
+  fl := new(big.Int).Div(lo.Num(), lo.Denom())
+  c := new(big.Rat).SetInt(fl)
+  if c.Cmp(lo) < 0 {
+    c.Add(c, big.NewRat(1, 1))
+  }
+  if c.Cmp(hi) <= 0 {
+    return c
+  }
+
+  // lo and hi share the integer part fl: recurse on the reciprocals of
+  // their fractional parts
+  f := new(big.Rat).SetInt(fl)
+  inner := simplest(
+    new(big.Rat).Inv(new(big.Rat).Sub(hi, f)),
+    new(big.Rat).Inv(new(big.Rat).Sub(lo, f)))
+  return f.Add(f, inner.Inv(inner))
+}
+
+func exact(x float64) (*big.Rat, error) {
+  if math.IsNaN(x) || math.IsInf(x, 0) {
+    return nil, ErrNotFinite
+  }
+  return new(big.Rat).SetFloat64(x), nil
+}
+
+func distance(a, b *big.Rat) *big.Rat {
+  d := new(big.Rat).Sub(a, b)
+  return d.Abs(d)
+}
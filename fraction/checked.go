@@ -0,0 +1,52 @@
+package fraction
+
+import (
+  "errors"
+)
+
+var (
+  ErrDivisionByZero = errors.New("fraction: division by zero")
+  ErrOverflow = errors.New("fraction: int overflow")
+)
+
+// Err returns ErrDivisionByZero if n has a zero denominator, which only
+// happens when N and D are set directly.
+func (n Fraction) Err() error {
+  if n.b == nil && n.D == 0 && n.N != 0 {
+    return ErrDivisionByZero
+  }
+  return nil
+}
+
+// The checked variants never switch to big.Rat storage. They return
+// ErrOverflow when the exact result does not fit in N and D, and
+// ErrDivisionByZero for a zero divisor or an operand with a zero denominator.
+
+func AddChecked(a, b Fraction) (Fraction, error) { return checked(Add, a, b) }
+func SubChecked(a, b Fraction) (Fraction, error) { return checked(Sub, a, b) }
+func MulChecked(a, b Fraction) (Fraction, error) { return checked(Mul, a, b) }
+
+func DivChecked(a, b Fraction) (Fraction, error) {
+  if b.Sign() == 0 {
+    return Fraction{}, ErrDivisionByZero
+  }
+  return checked(Div, a, b)
+}
+
+func NegChecked(a Fraction) (Fraction, error) {
+  return checked(Mul, a, Fraction{N: -1, D: 1})
+}
+
+func checked(op func(a, b Fraction) Fraction, a, b Fraction) (Fraction, error) {
+  if err := a.Err(); err != nil {
+    return Fraction{}, err
+  }
+  if err := b.Err(); err != nil {
+    return Fraction{}, err
+  }
+  res := op(a, b)
+  if res.b != nil {
+    return Fraction{}, ErrOverflow
+  }
+  return res, nil
+}
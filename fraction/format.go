@@ -0,0 +1,98 @@
+package fraction
+
+import (
+  "fmt"
+  "io"
+  "math/big"
+  "strings"
+)
+
+func (n Fraction) String() string {
+  switch {
+  case n.b != nil:
+    return n.b.RatString()
+  case n.D == 1 || n.N == 0:
+    return fmt.Sprintf("%d", n.N)
+  }
+  return fmt.Sprintf("%d/%d", n.N, n.D)
+}
+
+// Format implements fmt.Formatter. The verbs are
+//
+//  %v, %s  fraction form: 7/3
+//  %f      fixed decimal, rounded half away from zero: %.2f gives 2.33
+//  %m      mixed number: 2 1/3
+//
+// Width and the '-', '+' and ' ' flags work as for numbers, so "% -10v"
+// lines values up in a table.
+func (n Fraction) Format(f fmt.State, verb rune) {
+  var s string
+  switch verb {
+  case 'v', 's':
+    s = n.String()
+  case 'f', 'F':
+    prec, ok := f.Precision()
+    if !ok {
+      prec = 6
+    }
+    s = n.Decimal(prec)
+  case 'm':
+    s = n.Mixed()
+  default:
+    fmt.Fprintf(f, "%%!%c(fraction.Fraction=%s)", verb, n.String())
+    return
+  }
+  Pad(f, s)
+}
+
+// Pad writes s to f, applying the sign flags and width of f. Other number
+// types use it so that their values line up with fractions.
+func Pad(f fmt.State, s string) {
+  if !strings.HasPrefix(s, "-") {
+    if f.Flag('+') {
+      s = "+" + s
+    } else if f.Flag(' ') {
+      s = " " + s
+    }
+  }
+  w, ok := f.Width()
+  switch {
+  case !ok:
+    io.WriteString(f, s)
+  case f.Flag('-'):
+    fmt.Fprintf(f, "%-*s", w, s)
+  default:
+    fmt.Fprintf(f, "%*s", w, s)
+  }
+}
+
+// Decimal returns n as a decimal with prec digits after the point.
+func (n Fraction) Decimal(prec int) string {
+  return n.rat().FloatString(prec)
+}
+
+// Mixed returns n as a mixed number such as "-2 1/3".
+func (n Fraction) Mixed() string {
+  r := n.rat()
+  if r.IsInt() {
+    return r.Num().String()
+  }
+  q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+  if q.Sign() == 0 {
+    return n.String()
+  }
+  return fmt.Sprintf("%s %s/%s", q, m.Abs(m), r.Denom())
+}
+
+// Latex returns n in LaTeX notation, for example -\frac{3}{4}.
+func (n Fraction) Latex() string {
+  r := n.rat()
+  if r.IsInt() {
+    return r.Num().String()
+  }
+  sign := ""
+  if r.Sign() < 0 {
+    sign = "-"
+  }
+  return fmt.Sprintf("%s\\frac{%s}{%s}", sign, new(big.Int).Abs(r.Num()), r.Denom())
+}
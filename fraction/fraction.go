@@ -1,29 +1,129 @@
 package fraction
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
+	"math/bits"
+	"os"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
+// Fraction is an exact rational number. N and D hold the value while it fits
+// in an int; once an operation would overflow, the result is kept in b as a
+// big.Rat instead and N, D are left zero. The zero value is 0.
 type Fraction struct {
   N int
   D int
+  b *big.Rat
+}
+
+// New returns the simplified fraction n/d.
+func New(n, d int) Fraction {
+  res := Fraction{N: n, D: d}
+  res.Simplify()
+  return res
+}
+
+// FromRat converts r to a Fraction, using the int form whenever it fits.
+func FromRat(r *big.Rat) Fraction {
+  if f, ok := intForm(r); ok {
+    return f
+  }
+  return Fraction{b: new(big.Rat).Set(r)}
+}
+
+// intForm returns r with int fields, and false if it does not fit. The most
+// negative int is left to big.Rat so that the int form can always be negated.
+func intForm(r *big.Rat) (Fraction, bool) {
+  if r.Num().IsInt64() && r.Denom().IsInt64() {
+    n, d := r.Num().Int64(), r.Denom().Int64()
+    if int64(int(n)) == n && int64(int(d)) == d && int(n) != minInt {
+      return Fraction{N: int(n), D: int(d)}, true
+    }
+  }
+  return Fraction{}, false
+}
+
+// Rat returns the value of n as a newly allocated big.Rat.
+func (n Fraction) Rat() *big.Rat {
+  if n.b != nil {
+    return new(big.Rat).Set(n.b)
+  }
+  return n.rat()
+}
+
+// IsBig reports whether n has been promoted to big.Rat storage.
+func (n Fraction) IsBig() bool {
+  return n.b != nil
+}
+
+// rat is like Rat but may return the stored pointer, which must not be modified.
+func (n Fraction) rat() *big.Rat {
+  if n.b != nil {
+    return n.b
+  }
+  if n.D == 0 {
+    return new(big.Rat)
+  }
+  return big.NewRat(int64(n.N), int64(n.D))
+}
+
+func (n Fraction) Sign() int {
+  switch {
+  case n.b != nil:
+    return n.b.Sign()
+  case n.N == 0:
+    return 0
+  case (n.N < 0) != (n.D < 0):
+    return -1
+  }
+  return 1
 }
 
 func (n Fraction) gcd() int {
-  a := max(n.D, n.N)
-  b := min(n.D, n.N)
-  if b == 0 || a == 0 { return 1 }
-  f := 0
-  for {
-    if f = a % b; f == 0 { return b }
-    a = b
-    b = f
+  return int(binaryGCD(mag(n.N), mag(n.D)))
+}
+
+// binaryGCD is Stein's algorithm: it only shifts and subtracts. gcd(0, 0) is
+// taken as 1 so that the result can always be divided by.
+func binaryGCD(a, b uint64) uint64 {
+  switch {
+  case a == 0 && b == 0:
+    return 1
+  case a == 0:
+    return b
+  case b == 0:
+    return a
+  }
+  shift := bits.TrailingZeros64(a | b)
+  a >>= bits.TrailingZeros64(a)
+  for b != 0 {
+    b >>= bits.TrailingZeros64(b)
+    if a > b {
+      a, b = b, a
+    }
+    b -= a
   }
+  return a << shift
 }
 
 func (n *Fraction) Simplify() {
+  if n.b != nil {
+    *n = FromRat(n.b)
+    return
+  }
+  if n.N == 0 {
+    n.D = 1
+    return
+  }
+  if n.N == minInt || n.D == minInt {
+    *n = fromRat(n.rat())
+    return
+  }
+
   f := n.gcd();
   n.D /= f
   n.N /= f
@@ -34,73 +134,232 @@ func (n *Fraction) Simplify() {
   }
 }
 
-func (n *Fraction) reverse() {
-  n.N = n.N ^ n.D
-  n.D = n.N ^ n.D
-  n.N = n.N ^ n.D
+const minInt = -1 << (strconv.IntSize - 1)
+
+// mul and add return false instead of a wrapped result on int overflow.
+func mul(a, b int) (int, bool) {
+  if a == 0 || b == 0 {
+    return 0, true
+  }
+  c := a * b
+  if c/b != a || (a == -1 && b == minInt) || (b == -1 && a == minInt) {
+    return 0, false
+  }
+  return c, true
 }
 
-func Read(n *Fraction) {
-  var text string
-  fmt.Scan(&text)
-  text = strings.TrimSpace(text)
-  parts := strings.Split(text, "/")
-  if len(parts) > 0 {
-    n.N, _ = strconv.Atoi(parts[0])
-    n.D = 1
+func add(a, b int) (int, bool) {
+  c := a + b
+  if (a > 0 && b > 0 && c < 0) || (a < 0 && b < 0 && c >= 0) {
+    return 0, false
+  }
+  return c, true
+}
+
+// small returns a, b with the zero value replaced by 0/1, and whether both
+// can take the int fast path. Operands holding the most negative int take the
+// big path, so that every magnitude and gcd fits in an int.
+func small(a, b Fraction) (Fraction, Fraction, bool) {
+  if a.b != nil || b.b != nil {
+    return a, b, false
+  }
+  if a.N == minInt || a.D == minInt || b.N == minInt || b.D == minInt {
+    return a, b, false
+  }
+  if a.N == 0 && a.D == 0 {
+    a.D = 1
+  }
+  if b.N == 0 && b.D == 0 {
+    b.D = 1
+  }
+  return a, b, true
+}
+
+var errSyntax = errors.New("invalid number")
+
+var (
+  decimalRe = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+  properRe = regexp.MustCompile(`^\d+/\d+$`)
+)
+
+// Parse reads a number written as an integer ("3"), a fraction ("-3/4"), a
+// decimal ("0.25"), scientific notation ("1.5e-2") or a mixed number
+// ("2 1/3"). Values of any size are accepted and converted exactly.
+func Parse(s string) (Fraction, error) {
+  s = strings.TrimSpace(s)
+  if fields := strings.Fields(s); len(fields) == 2 {
+    return parseMixed(fields[0], fields[1])
   }
-  if len(parts) > 1 {
-    n.D, _ = strconv.Atoi(parts[1])
+
+  parts := strings.Split(s, "/")
+  if len(parts) > 2 {
+    return Fraction{}, fmt.Errorf("%w: %q", errSyntax, s)
+  }
+  num, err := parseDecimal(parts[0])
+  if err != nil || len(parts) == 1 {
+    return num, err
+  }
+  den, err := parseDecimal(parts[1])
+  if err != nil {
+    return Fraction{}, err
+  }
+  if den.Sign() == 0 {
+    return Fraction{}, fmt.Errorf("%w: %q", ErrDivisionByZero, s)
   }
+  return Div(num, den), nil
+}
 
-  n.Simplify()
+// parseMixed reads a whole number followed by a proper fraction, as in
+// "-2 1/3". The sign of the whole part applies to the fraction too.
+func parseMixed(whole, part string) (Fraction, error) {
+  w, err := strconv.Atoi(whole)
+  if err != nil || !properRe.MatchString(part) {
+    return Fraction{}, fmt.Errorf("%w: %q", errSyntax, whole+" "+part)
+  }
+  f, err := Parse(part)
+  if err != nil {
+    return Fraction{}, err
+  }
+  if strings.HasPrefix(whole, "-") {
+    return Sub(Fraction{N: w, D: 1}, f), nil
+  }
+  return Add(Fraction{N: w, D: 1}, f), nil
 }
 
-func Print(n *Fraction, p uint) {
-  var buffer string
-  switch {
-  case n.N < 0 && n.D == 1:
-    buffer = fmt.Sprintf("%d", n.N)
-  case n.N < 0:
-    buffer = fmt.Sprintf("%d/%d", n.N, n.D)
-  case n.D == 1:
-    buffer = fmt.Sprintf(" %d", n.N)
-  default:
-    buffer = fmt.Sprintf(" %d/%d", n.N, n.D)
+func parseDecimal(s string) (Fraction, error) {
+  s = strings.TrimSpace(s)
+  if i, err := strconv.Atoi(s); err == nil {
+    return Fraction{N: i, D: 1}, nil
   }
+  if decimalRe.MatchString(s) {
+    if r, ok := new(big.Rat).SetString(s); ok {
+      return FromRat(r), nil
+    }
+  }
+  return Fraction{}, fmt.Errorf("%w: %q", errSyntax, s)
+}
 
-  fmt.Printf("%-*s", p, buffer)
+// Read reads one number, in any form Parse accepts, from a line of standard
+// input.
+func Read(n *Fraction) {
+  var line []byte
+  b := make([]byte, 1)
+  for {
+    if k, err := os.Stdin.Read(b); k == 0 || err != nil || b[0] == '\n' {
+      break
+    }
+    line = append(line, b[0])
+  }
+  *n, _ = Parse(string(line))
+}
+
+func Print(n *Fraction, p uint) {
+  fmt.Printf("% -*v", p, *n)
 }
 
 func Add(a, b Fraction) (res Fraction) {
-  res.N = a.N * b.D + a.D * b.N
-  res.D = a.D * b.D
-  res.Simplify()
+  res.AddTo(a, b)
   return
 }
 
 func Sub(a, b Fraction) (res Fraction) {
-  res.N = a.N * b.D - b.N * a.D
-  res.D = a.D * b.D
-  res.Simplify()
+  res.SubTo(a, b)
   return
 }
 
 func Mul(a, b Fraction) (res Fraction) {
-  res.N = a.N * b.N
-  res.D = a.D * b.D
-  res.Simplify()
+  res.MulTo(a, b)
   return
 }
 
+// Div panics with ErrDivisionByZero if b is zero; DivChecked returns the error
+// instead.
 func Div(a, b Fraction) (res Fraction) {
-  res.N = a.N * b.D
-  res.D = a.D * b.N
-  res.Simplify()
+  res.DivTo(a, b)
   return
 }
 
+// Cmp returns -1, 0 or +1 depending on whether a is less than, equal to or
+// greater than b. Cross products are taken in 128 bits, so it never overflows.
+func Cmp(a, b Fraction) int {
+  a, b, ok := small(a, b)
+  if !ok {
+    return a.rat().Cmp(b.rat())
+  }
+  sa, sb := a.Sign(), b.Sign()
+  switch {
+  case sa < sb:
+    return -1
+  case sa > sb:
+    return 1
+  case sa == 0:
+    return 0
+  }
+
+  // Same sign: compare |a.N| * |b.D| against |b.N| * |a.D|
+  hi1, lo1 := bits.Mul64(mag(a.N), mag(b.D))
+  hi2, lo2 := bits.Mul64(mag(b.N), mag(a.D))
+  c := 0
+  switch {
+  case hi1 < hi2 || (hi1 == hi2 && lo1 < lo2):
+    c = -1
+  case hi1 > hi2 || (hi1 == hi2 && lo1 > lo2):
+    c = 1
+  }
+  return c * sa
+}
+
+// mag returns |x|, which always fits in a uint64.
+func mag(x int) uint64 {
+  if x < 0 {
+    return uint64(-(x + 1)) + 1
+  }
+  return uint64(x)
+}
+
+func Abs(a Fraction) Fraction {
+  if a.Sign() < 0 {
+    return Neg(a)
+  }
+  return a
+}
+
+func Min(a, b Fraction) Fraction {
+  if Cmp(b, a) < 0 {
+    return b
+  }
+  return a
+}
+
+func Max(a, b Fraction) Fraction {
+  if Cmp(b, a) > 0 {
+    return b
+  }
+  return a
+}
+
 func Neg(a Fraction) (res Fraction) {
-  res = Mul(a, Fraction{-1, 1})
+  res = Mul(a, Fraction{N: -1, D: 1})
   return
 }
+
+// Method forms of the arithmetic above, so that Fraction can be used as a
+// field.Element.
+
+func (n Fraction) Add(b Fraction) Fraction { return Add(n, b) }
+func (n Fraction) Sub(b Fraction) Fraction { return Sub(n, b) }
+func (n Fraction) Mul(b Fraction) Fraction { return Mul(n, b) }
+func (n Fraction) Div(b Fraction) Fraction { return Div(n, b) }
+func (n Fraction) Neg() Fraction { return Neg(n) }
+
+func (n Fraction) Cmp(b Fraction) int { return Cmp(n, b) }
+func (n Fraction) Less(b Fraction) bool { return Cmp(n, b) < 0 }
+func (n Fraction) Abs() Fraction { return Abs(n) }
+
+func (n Fraction) IsZero() bool {
+  return n.Sign() == 0
+}
+
+func (n Fraction) FromFraction(f Fraction) Fraction {
+  return f
+}
@@ -0,0 +1,125 @@
+package fraction
+
+import (
+  "math"
+  "math/big"
+  "testing"
+)
+
+func TestOverflowRoundTrip(t *testing.T) {
+  max := New(math.MaxInt, 1)
+  four := New(4, 1)
+
+  x := Mul(max, four)
+  if !x.IsBig() {
+    t.Fatalf("MaxInt * 4 = %v is not promoted to big.Rat", x)
+  }
+  want := new(big.Rat).Mul(big.NewRat(math.MaxInt, 1), big.NewRat(4, 1))
+  if x.Rat().Cmp(want) != 0 {
+    t.Fatalf("MaxInt * 4 = %v, want %v", x, want.RatString())
+  }
+
+  y := Div(x, four)
+  if y.IsBig() || y.N != math.MaxInt || y.D != 1 {
+    t.Errorf("MaxInt * 4 / 4 = %+v, want MaxInt in the int form", y)
+  }
+
+  s := Add(max, New(1, 1))
+  if !s.IsBig() {
+    t.Fatalf("MaxInt + 1 = %v is not promoted to big.Rat", s)
+  }
+  if d := Sub(s, New(1, 1)); d.IsBig() || Cmp(d, max) != 0 {
+    t.Errorf("MaxInt + 1 - 1 = %+v, want MaxInt in the int form", d)
+  }
+}
+
+func TestMinInt(t *testing.T) {
+  tests := []struct {
+    name string
+    got Fraction
+    want *big.Rat
+    big bool
+  }{
+    {"minInt/1", New(minInt, 1), big.NewRat(minInt, 1), true},
+    {"minInt/-1", New(minInt, -1), new(big.Rat).Neg(big.NewRat(minInt, 1)), true},
+    {"-(minInt)", Neg(New(minInt, 1)), new(big.Rat).Neg(big.NewRat(minInt, 1)), true},
+    {"4/minInt", New(4, minInt), big.NewRat(-1, 1<<61), false},
+    {"minInt/minInt", New(minInt, minInt), big.NewRat(1, 1), false},
+    {"minInt/2", New(minInt, 2), big.NewRat(minInt/2, 1), false},
+    {"minInt+1", Add(New(minInt, 1), New(1, 1)), big.NewRat(minInt+1, 1), false},
+    {"|minInt|", Abs(New(minInt, 1)), new(big.Rat).Neg(big.NewRat(minInt, 1)), true},
+  }
+  for _, tt := range tests {
+    if tt.got.Rat().Cmp(tt.want) != 0 {
+      t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want.RatString())
+    }
+    if tt.got.IsBig() != tt.big {
+      t.Errorf("%s: IsBig() = %v, want %v", tt.name, tt.got.IsBig(), tt.big)
+    }
+    if err := tt.got.Err(); err != nil {
+      t.Errorf("%s: Err() = %v", tt.name, err)
+    }
+  }
+}
+
+func TestCmp(t *testing.T) {
+  huge := Mul(New(math.MaxInt, 1), New(math.MaxInt, 1))
+  tests := []struct {
+    a, b Fraction
+    want int
+  }{
+    {New(-1, 3), New(-1, 2), 1},
+    {New(-1, 2), New(-1, 3), -1},
+    {New(1, 3), New(1, 2), -1},
+    {New(2, 4), New(1, 2), 0},
+    {Fraction{}, New(0, 1), 0},
+    {New(-1, 3), Fraction{}, -1},
+    {New(math.MaxInt, 3), New(math.MaxInt-1, 3), 1},
+    {New(math.MaxInt-1, math.MaxInt), New(math.MaxInt-2, math.MaxInt-1), 1},
+    {New(minInt, 1), New(minInt+1, 1), -1},
+    {huge, New(math.MaxInt, 1), 1},
+    {Neg(huge), New(minInt, 1), -1},
+  }
+  for _, tt := range tests {
+    if got := Cmp(tt.a, tt.b); got != tt.want {
+      t.Errorf("Cmp(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+    }
+  }
+}
+
+// TestArithmetic checks the int path and the promotion to big.Rat against
+// big.Rat on values near the limits of int
+func TestArithmetic(t *testing.T) {
+  values := []Fraction{
+    New(0, 1), New(1, 1), New(-1, 1), New(1, 3), New(-7, 2),
+    New(math.MaxInt, 1), New(math.MaxInt-1, 1), New(minInt+1, 1), New(minInt, 1),
+    New(1, math.MaxInt), New(math.MaxInt, math.MaxInt-1), New(1<<32, 3), New(-3, 1<<31),
+  }
+  ops := []struct {
+    name string
+    f func(a, b Fraction) Fraction
+    r func(z, a, b *big.Rat) *big.Rat
+  }{
+    {"+", Add, (*big.Rat).Add},
+    {"-", Sub, (*big.Rat).Sub},
+    {"*", Mul, (*big.Rat).Mul},
+    {"/", Div, (*big.Rat).Quo},
+  }
+  for _, a := range values {
+    for _, b := range values {
+      for _, op := range ops {
+        if op.name == "/" && b.Sign() == 0 {
+          continue
+        }
+        got := op.f(a, b)
+        want := op.r(new(big.Rat), a.Rat(), b.Rat())
+        if got.Rat().Cmp(want) != 0 {
+          t.Errorf("%v %s %v = %v, want %v", a, op.name, b, got, want.RatString())
+        }
+        if _, fits := intForm(want); got.IsBig() == fits {
+          t.Errorf("%v %s %v: IsBig() = %v although the int form fits = %v", a, op.name, b, got.IsBig(), fits)
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,127 @@
+package fraction
+
+import (
+  "math/big"
+)
+
+// The To methods store their result in the receiver and return it, in the
+// style of math/big, so that hot loops can update values where they are. The
+// operands may alias the receiver. On the int path nothing is allocated.
+
+func (z *Fraction) AddTo(a, b Fraction) *Fraction {
+  if a, b, ok := small(a, b); ok && z.addSmall(a, b) {
+    return z
+  }
+  *z = fromRat(new(big.Rat).Add(a.rat(), b.rat()))
+  return z
+}
+
+func (z *Fraction) SubTo(a, b Fraction) *Fraction {
+  if a, b, ok := small(a, b); ok && z.addSmall(a, Fraction{N: -b.N, D: b.D}) {
+    return z
+  }
+  *z = fromRat(new(big.Rat).Sub(a.rat(), b.rat()))
+  return z
+}
+
+func (z *Fraction) MulTo(a, b Fraction) *Fraction {
+  if a, b, ok := small(a, b); ok && z.mulSmall(a, b) {
+    return z
+  }
+  *z = fromRat(new(big.Rat).Mul(a.rat(), b.rat()))
+  return z
+}
+
+// DivTo panics with ErrDivisionByZero if b is zero.
+func (z *Fraction) DivTo(a, b Fraction) *Fraction {
+  if b.Sign() == 0 {
+    panic(ErrDivisionByZero)
+  }
+  if a, b, ok := small(a, b); ok && z.mulSmall(a, Fraction{N: b.D, D: b.N}) {
+    return z
+  }
+  *z = fromRat(new(big.Rat).Quo(a.rat(), b.rat()))
+  return z
+}
+
+func (z *Fraction) NegTo(a Fraction) *Fraction {
+  return z.MulTo(a, Fraction{N: -1, D: 1})
+}
+
+// SubMulDiv sets z to a - b*c/p, which is the Jordan exchange formula
+// (a*p - b*c)/p, and returns z. It skips the work when b or c is zero and
+// panics with ErrDivisionByZero if p is zero.
+func (z *Fraction) SubMulDiv(a, b, c, p Fraction) *Fraction {
+  if p.Sign() == 0 {
+    panic(ErrDivisionByZero)
+  }
+  if b.Sign() == 0 || c.Sign() == 0 {
+    *z = a
+    return z
+  }
+  if a, b, ok := small(a, b); ok {
+    if c, p, ok := small(c, p); ok {
+      // q = b*c/p, checking after each step that it has not been promoted
+      var q Fraction
+      if q.mulSmall(b, c) && q.b == nil &&
+        q.mulSmall(q, Fraction{N: p.D, D: p.N}) && q.b == nil &&
+        z.addSmall(a, Fraction{N: -q.N, D: q.D}) {
+        return z
+      }
+    }
+  }
+
+  // Big path: put everything over one denominator so that only a single gcd
+  // is taken, a_n b_d c_d p_n - b_n c_n p_d a_d over a_d b_d c_d p_n
+  ar, br, cr, pr := a.rat(), b.rat(), c.rat(), p.rat()
+  bc := new(big.Int).Mul(br.Denom(), cr.Denom())
+  den := new(big.Int).Mul(bc, pr.Num())
+  num := new(big.Int).Mul(ar.Num(), den)
+  t := new(big.Int).Mul(br.Num(), cr.Num())
+  t.Mul(t, pr.Denom())
+  t.Mul(t, ar.Denom())
+  num.Sub(num, t)
+  den.Mul(den, ar.Denom())
+  *z = fromRat(new(big.Rat).SetFrac(num, den))
+  return z
+}
+
+// addSmall sets z to a + b on the int path, dividing the denominators by
+// their gcd first to keep the products small. It reports false on overflow.
+func (z *Fraction) addSmall(a, b Fraction) bool {
+  g := int(binaryGCD(mag(a.D), mag(b.D)))
+  x, ok1 := mul(a.N, b.D/g)
+  y, ok2 := mul(b.N, a.D/g)
+  n, ok3 := add(x, y)
+  d, ok4 := mul(a.D, b.D/g)
+  if !(ok1 && ok2 && ok3 && ok4) {
+    return false
+  }
+  z.N, z.D, z.b = n, d, nil
+  z.Simplify()
+  return true
+}
+
+// mulSmall sets z to a * b on the int path, cancelling each numerator
+// against the other denominator first. It reports false on overflow.
+func (z *Fraction) mulSmall(a, b Fraction) bool {
+  g1 := int(binaryGCD(mag(a.N), mag(b.D)))
+  g2 := int(binaryGCD(mag(b.N), mag(a.D)))
+  n, ok1 := mul(a.N/g1, b.N/g2)
+  d, ok2 := mul(a.D/g2, b.D/g1)
+  if !(ok1 && ok2) {
+    return false
+  }
+  z.N, z.D, z.b = n, d, nil
+  z.Simplify()
+  return true
+}
+
+// fromRat is FromRat for a big.Rat the caller no longer uses, which it keeps
+// instead of copying.
+func fromRat(r *big.Rat) Fraction {
+  if f, ok := intForm(r); ok {
+    return f
+  }
+  return Fraction{b: r}
+}
@@ -0,0 +1,89 @@
+package fraction
+
+import (
+  "math"
+  "math/big"
+  "testing"
+)
+
+// subMulDiv is a - b*c/p in big.Rat
+func subMulDiv(a, b, c, p Fraction) *big.Rat {
+  q := new(big.Rat).Mul(b.Rat(), c.Rat())
+  q.Quo(q, p.Rat())
+  return q.Sub(a.Rat(), q)
+}
+
+func TestSubMulDiv(t *testing.T) {
+  max := New(math.MaxInt, 1)
+  tests := []struct {
+    name string
+    a, b, c, p Fraction
+    big bool // The result needs big.Rat
+  }{
+    {"int path", New(1, 2), New(1, 3), New(3, 4), New(5, 6), false},
+    {"zero b", New(7, 3), New(0, 1), max, New(2, 1), false},
+    {"zero c", New(7, 3), max, Fraction{}, New(2, 1), false},
+    {"negative pivot", New(-5, 1), New(2, 1), New(3, 1), New(-4, 1), false},
+    {"overflowing product", New(1, 1), max, max, New(1, 1), true},
+    {"overflow cancelled by p", New(3, 1), max, New(2, 1), max, false},
+    {"overflowing difference", New(minInt+1, 1), New(1, 1), New(2, 1), New(1, 1), true},
+    {"big operands", Mul(max, New(4, 1)), New(1, 4), New(1, 1), New(1, 1), true},
+    {"big operands, int result", Mul(max, New(4, 1)), max, New(4, 1), New(1, 1), false},
+    {"most negative int", New(minInt, 1), New(-1, 1), New(1, 1), New(1, 1), false},
+  }
+  for _, tt := range tests {
+    var z Fraction
+    z.SubMulDiv(tt.a, tt.b, tt.c, tt.p)
+    want := subMulDiv(tt.a, tt.b, tt.c, tt.p)
+    if z.Rat().Cmp(want) != 0 {
+      t.Errorf("%s: SubMulDiv(%v, %v, %v, %v) = %v, want %v", tt.name, tt.a, tt.b, tt.c, tt.p, z, want.RatString())
+    }
+    if z.IsBig() != tt.big {
+      t.Errorf("%s: IsBig() = %v, want %v", tt.name, z.IsBig(), tt.big)
+    }
+
+    // The receiver may alias an operand
+    a := tt.a
+    a.SubMulDiv(a, tt.b, tt.c, tt.p)
+    if a.Rat().Cmp(want) != 0 {
+      t.Errorf("%s: aliased SubMulDiv = %v, want %v", tt.name, a, want.RatString())
+    }
+  }
+}
+
+func TestSubMulDivZeroPivot(t *testing.T) {
+  defer func() {
+    if r := recover(); r != ErrDivisionByZero {
+      t.Errorf("SubMulDiv with p = 0 panicked with %v, want %v", r, ErrDivisionByZero)
+    }
+  }()
+  var z Fraction
+  z.SubMulDiv(New(1, 1), New(1, 1), New(1, 1), New(0, 1))
+}
+
+func TestInPlace(t *testing.T) {
+  max := New(math.MaxInt, 1)
+  values := []Fraction{New(0, 1), New(2, 3), New(-5, 7), max, New(minInt, 1), Mul(max, max)}
+  for _, a := range values {
+    for _, b := range values {
+      z := a
+      if got, want := z.AddTo(z, b), new(big.Rat).Add(a.Rat(), b.Rat()); got.Rat().Cmp(want) != 0 {
+        t.Errorf("AddTo(%v, %v) = %v, want %v", a, b, got, want.RatString())
+      }
+      z = a
+      if got, want := z.SubTo(z, b), new(big.Rat).Sub(a.Rat(), b.Rat()); got.Rat().Cmp(want) != 0 {
+        t.Errorf("SubTo(%v, %v) = %v, want %v", a, b, got, want.RatString())
+      }
+      z = a
+      if got, want := z.MulTo(z, b), new(big.Rat).Mul(a.Rat(), b.Rat()); got.Rat().Cmp(want) != 0 {
+        t.Errorf("MulTo(%v, %v) = %v, want %v", a, b, got, want.RatString())
+      }
+      if b.Sign() != 0 {
+        z = a
+        if got, want := z.DivTo(z, b), new(big.Rat).Quo(a.Rat(), b.Rat()); got.Rat().Cmp(want) != 0 {
+          t.Errorf("DivTo(%v, %v) = %v, want %v", a, b, got, want.RatString())
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,173 @@
+package fraction
+
+import (
+  "bytes"
+  "encoding/binary"
+  "encoding/json"
+  "errors"
+  "fmt"
+  "math/big"
+)
+
+// JSONForm selects how MarshalJSONForm writes a Fraction.
+type JSONForm int
+
+const (
+  // JSONString writes "3/4".
+  JSONString JSONForm = iota
+  // JSONNumber writes 0.75 when the value has a finite decimal expansion
+  // and falls back to the string form, e.g. "1/3", when it does not.
+  JSONNumber
+)
+
+func (n Fraction) MarshalText() ([]byte, error) {
+  if err := n.Err(); err != nil {
+    return nil, err
+  }
+  return []byte(n.String()), nil
+}
+
+// UnmarshalText accepts anything Parse does and stores the simplified value.
+func (n *Fraction) UnmarshalText(text []byte) error {
+  f, err := Parse(string(text))
+  if err != nil {
+    return err
+  }
+  *n = f
+  return nil
+}
+
+// MarshalJSON writes n in the string form. UnmarshalJSON accepts every form.
+func (n Fraction) MarshalJSON() ([]byte, error) {
+  return n.MarshalJSONForm(JSONString)
+}
+
+// MarshalJSONForm writes n in the given form
+func (n Fraction) MarshalJSONForm(form JSONForm) ([]byte, error) {
+  if err := n.Err(); err != nil {
+    return nil, err
+  }
+  if form == JSONNumber {
+    if prec, ok := decimalDigits(n.rat().Denom()); ok {
+      return []byte(n.Decimal(prec)), nil
+    }
+  }
+  return json.Marshal(n.String())
+}
+
+// UnmarshalJSON accepts a string ("3/4", "2 1/3", "0.75"), a JSON number and
+// the {"N":3,"D":4} object that Fraction used to encode to.
+func (n *Fraction) UnmarshalJSON(data []byte) error {
+  data = bytes.TrimSpace(data)
+  switch {
+  case bytes.Equal(data, []byte("null")):
+    return nil
+  case bytes.HasPrefix(data, []byte(`"`)):
+    var s string
+    if err := json.Unmarshal(data, &s); err != nil {
+      return err
+    }
+    return n.UnmarshalText([]byte(s))
+  case bytes.HasPrefix(data, []byte("{")):
+    var v struct{ N, D int }
+    if err := json.Unmarshal(data, &v); err != nil {
+      return err
+    }
+    if v.D == 0 {
+      return fmt.Errorf("%w: %s", ErrDivisionByZero, data)
+    }
+    *n = New(v.N, v.D)
+    return nil
+  }
+  return n.UnmarshalText(data)
+}
+
+// Number is a Fraction that encodes to JSON in the JSONNumber form, for
+// fields that should read as numbers. It decodes like a Fraction.
+type Number struct {
+  Fraction
+}
+
+func (n Number) MarshalJSON() ([]byte, error) {
+  return n.MarshalJSONForm(JSONNumber)
+}
+
+// decimalDigits returns how many digits after the point are needed to write
+// 1/d exactly, and false if 1/d does not terminate.
+func decimalDigits(d *big.Int) (int, bool) {
+  d = new(big.Int).Set(d)
+  twos, fives := 0, 0
+  two, five, m := big.NewInt(2), big.NewInt(5), new(big.Int)
+  for d.Cmp(big.NewInt(1)) > 0 {
+    switch {
+    case m.Mod(d, two).Sign() == 0:
+      d.Quo(d, two)
+      twos++
+    case m.Mod(d, five).Sign() == 0:
+      d.Quo(d, five)
+      fives++
+    default:
+      return 0, false
+    }
+  }
+  return max(twos, fives), true
+}
+
+// Binary encoding: a tag byte, then either the varint N and uvarint D (tag
+// binaryInt) or the gob encoding of the big.Rat (tag binaryBig).
+const (
+  binaryInt byte = 1
+  binaryBig byte = 2
+)
+
+func (n Fraction) MarshalBinary() ([]byte, error) {
+  if err := n.Err(); err != nil {
+    return nil, err
+  }
+  if n.b != nil {
+    buf, err := n.b.GobEncode()
+    if err != nil {
+      return nil, err
+    }
+    return append([]byte{binaryBig}, buf...), nil
+  }
+  n.Simplify()
+  buf := []byte{binaryInt}
+  buf = binary.AppendVarint(buf, int64(n.N))
+  return binary.AppendUvarint(buf, uint64(n.D)), nil
+}
+
+var errBinary = errors.New("fraction: invalid binary encoding")
+
+func (n *Fraction) UnmarshalBinary(data []byte) error {
+  if len(data) == 0 {
+    return errBinary
+  }
+  switch data[0] {
+  case binaryInt:
+    num, k := binary.Varint(data[1:])
+    if k <= 0 {
+      return errBinary
+    }
+    den, l := binary.Uvarint(data[1+k:])
+    if l <= 0 || 1+k+l != len(data) {
+      return errBinary
+    }
+    if den == 0 {
+      return ErrDivisionByZero
+    }
+    *n = FromRat(new(big.Rat).SetFrac(big.NewInt(num), new(big.Int).SetUint64(den)))
+    return nil
+  case binaryBig:
+    r := new(big.Rat)
+    if err := r.GobDecode(data[1:]); err != nil {
+      return err
+    }
+    if r.Denom().Sign() == 0 {
+      return ErrDivisionByZero
+    }
+    *n = FromRat(r)
+    return nil
+  }
+  return errBinary
+}
@@ -0,0 +1,302 @@
+package linalg
+
+import (
+	"fmt"
+
+	"simplex/field"
+)
+
+// Op is the kind of an elementary row operation
+type Op int
+
+const (
+	Swap        Op = iota // exchange rows Row and Src
+	Scale                 // multiply row Row by Factor
+	AddMultiple           // add Factor times row Src to row Row
+)
+
+// Step is one elementary row operation and the matrix it produced
+type Step[T field.Element[T]] struct {
+	Op     Op
+	Row    int
+	Src    int
+	Factor T
+	Result Matrix[T]
+}
+
+func (s Step[T]) String() string {
+	switch s.Op {
+	case Swap:
+		return fmt.Sprintf("R%d <-> R%d", s.Row+1, s.Src+1)
+	case Scale:
+		return fmt.Sprintf("R%d <- %v R%d", s.Row+1, s.Factor, s.Row+1)
+	}
+	return fmt.Sprintf("R%d <- R%d + (%v) R%d", s.Row+1, s.Row+1, s.Factor, s.Src+1)
+}
+
+// reducer applies row operations to a matrix in place, recording them when
+// log is not nil
+type reducer[T field.Element[T]] struct {
+	m   Matrix[T]
+	log *[]Step[T]
+}
+
+func (r reducer[T]) record(s Step[T]) {
+	if r.log != nil {
+		s.Result = r.m.Copy()
+		*r.log = append(*r.log, s)
+	}
+}
+
+func (r reducer[T]) swap(i, k int) {
+	r.m[i], r.m[k] = r.m[k], r.m[i]
+	r.record(Step[T]{Op: Swap, Row: i, Src: k})
+}
+
+func (r reducer[T]) scale(i int, f T) {
+	for j := range r.m[i] {
+		r.m[i][j] = r.m[i][j].Mul(f)
+	}
+	r.record(Step[T]{Op: Scale, Row: i, Factor: f})
+}
+
+func (r reducer[T]) addMultiple(i, k int, f T) {
+	for j := range r.m[i] {
+		r.m[i][j] = r.m[i][j].Add(f.Mul(r.m[k][j]))
+	}
+	r.record(Step[T]{Op: AddMultiple, Row: i, Src: k, Factor: f})
+}
+
+// reduce brings the first cols columns of r.m to reduced row echelon form by
+// Gauss-Jordan elimination and returns the pivot column of each nonzero row
+func (r reducer[T]) reduce(cols int) []int {
+	pivots := make([]int, 0, min(cols, len(r.m)))
+	row := 0
+	for c := 0; c < cols && row < len(r.m); c++ {
+		p := -1
+		for i := row; i < len(r.m); i++ {
+			if !r.m[i][c].IsZero() {
+				p = i
+				break
+			}
+		}
+		if p == -1 {
+			continue
+		}
+		if p != row {
+			r.swap(row, p)
+		}
+		if one := field.One[T](); r.m[row][c].Cmp(one) != 0 {
+			r.scale(row, one.Div(r.m[row][c]))
+		}
+		for i := range r.m {
+			if i != row && !r.m[i][c].IsZero() {
+				r.addMultiple(i, row, r.m[i][c].Neg())
+			}
+		}
+		pivots = append(pivots, c)
+		row++
+	}
+	return pivots
+}
+
+// RREF returns the reduced row echelon form of m, the pivot columns and the
+// row operations that produced it
+func RREF[T field.Element[T]](m Matrix[T]) (Matrix[T], []int, []Step[T]) {
+	var log []Step[T]
+	r := reducer[T]{m: m.Copy(), log: &log}
+	pivots := r.reduce(m.Cols())
+	return r.m, pivots, log
+}
+
+// Bareiss returns the fraction-free echelon form of m and the number of row
+// swaps it took. Every division in the algorithm is exact, so an integer
+// matrix stays integer and its entries stay as small as its minors.
+func Bareiss[T field.Element[T]](m Matrix[T]) (Matrix[T], int) {
+	a := m.Copy()
+	prev := field.One[T]()
+	swaps, row := 0, 0
+	for c := 0; c < a.Cols() && row < a.Rows(); c++ {
+		p := -1
+		for i := row; i < a.Rows(); i++ {
+			if !a[i][c].IsZero() {
+				p = i
+				break
+			}
+		}
+		if p == -1 {
+			continue
+		}
+		if p != row {
+			a[row], a[p] = a[p], a[row]
+			swaps++
+		}
+		for i := row + 1; i < a.Rows(); i++ {
+			for j := c + 1; j < a.Cols(); j++ {
+				// a_ij = (a_ij * a_rc - a_ic * a_rj) / previous pivot
+				a[i][j] = a[i][j].Mul(a[row][c]).Sub(a[i][c].Mul(a[row][j])).Div(prev)
+			}
+			var zero T
+			a[i][c] = zero
+		}
+		prev = a[row][c]
+		row++
+	}
+	return a, swaps
+}
+
+// Det returns the determinant of a square matrix, computed by Bareiss
+// elimination
+func Det[T field.Element[T]](m Matrix[T]) (T, error) {
+	n := m.Rows()
+	if n != m.Cols() {
+		var zero T
+		return zero, ErrDimension
+	}
+	if n == 0 {
+		return field.One[T](), nil
+	}
+	a, swaps := Bareiss(m)
+	det := a[n-1][n-1]
+	if swaps%2 == 1 {
+		det = det.Neg()
+	}
+	return det, nil
+}
+
+// Rank returns the number of linearly independent rows of m
+func Rank[T field.Element[T]](m Matrix[T]) int {
+	_, pivots, _ := RREF(m)
+	return len(pivots)
+}
+
+// Inverse returns the inverse of a square matrix by Gauss-Jordan elimination
+// on [m | I]
+func Inverse[T field.Element[T]](m Matrix[T]) (Matrix[T], error) {
+	n := m.Rows()
+	if n != m.Cols() {
+		return nil, ErrDimension
+	}
+	a, _ := m.Augment(Identity[T](n))
+	r := reducer[T]{m: a}
+	if len(r.reduce(n)) < n {
+		return nil, ErrSingular
+	}
+	inv := New[T](n, n)
+	for i := range inv {
+		copy(inv[i], a[i][n:])
+	}
+	return inv, nil
+}
+
+// NullSpace returns a basis of the solutions of m x = 0, one vector per free
+// column of the reduced row echelon form
+func NullSpace[T field.Element[T]](m Matrix[T]) []Vector[T] {
+	rref, pivots, _ := RREF(m)
+	isPivot := make([]bool, m.Cols())
+	for _, c := range pivots {
+		isPivot[c] = true
+	}
+	var basis []Vector[T]
+	for f := 0; f < m.Cols(); f++ {
+		if isPivot[f] {
+			continue
+		}
+		v := make(Vector[T], m.Cols())
+		v[f] = field.One[T]()
+		for i, c := range pivots {
+			v[c] = rref[i][f].Neg()
+		}
+		basis = append(basis, v)
+	}
+	return basis
+}
+
+// Solve solves m x = b and returns the row operations used. When the system
+// has more than one solution, the free variables are set to 0; NullSpace
+// gives the directions along which x can move.
+func Solve[T field.Element[T]](m Matrix[T], b Vector[T]) (Vector[T], []Step[T], error) {
+	if m.Rows() != len(b) {
+		return nil, nil, ErrDimension
+	}
+	a, _ := m.Augment(Column(b))
+	var log []Step[T]
+	r := reducer[T]{m: a, log: &log}
+	pivots := r.reduce(m.Cols())
+
+	n := m.Cols()
+	for i := len(pivots); i < len(a); i++ {
+		if !a[i][n].IsZero() {
+			return nil, log, ErrInconsistent
+		}
+	}
+	x := make(Vector[T], n)
+	for i, c := range pivots {
+		x[c] = a[i][n]
+	}
+	return x, log, nil
+}
+
+// Exchange returns the result of the Jordan exchange step on m[r][s], see
+// ExchangeInPlace. m is not modified.
+func Exchange[T field.Element[T]](m Matrix[T], r, s int) (Matrix[T], error) {
+	if r < 0 || r >= m.Rows() || s < 0 || s >= m.Cols() {
+		return nil, ErrDimension
+	}
+	if m[r][s].IsZero() {
+		return nil, ErrSingular
+	}
+	e := m.Copy()
+	ExchangeInPlace(e, r, s)
+	return e, nil
+}
+
+// ExchangeInPlace performs the Jordan exchange step that tableau.Exchange
+// applies, pivoting on m[r][s], which must be nonzero: the pivot becomes
+// 1/p, the rest of its row is divided by p, the rest of its column by -p, and
+// every other entry becomes (a_ij * p - a_is * a_rj) / p. Each entry outside
+// the pivot row and column is computed from values that are not yet
+// overwritten, so no copy is needed, and rows or columns with a zero in the
+// pivot column or row are left untouched.
+func ExchangeInPlace[T field.Element[T]](m Matrix[T], r, s int) {
+	p := m[r][s]
+	pivotRow := m[r]
+	_, fused := any(&p).(fusedExchange[T])
+
+	for i := range m {
+		if i == r {
+			continue
+		}
+		row := m[i]
+		if !row[s].IsZero() {
+			for j := range row {
+				if j != s && !pivotRow[j].IsZero() {
+					fill(&row[j], row[s], pivotRow[j], p, fused)
+				}
+			}
+		}
+		row[s] = row[s].Div(p).Neg()
+	}
+
+	for j := range pivotRow {
+		if j != s {
+			pivotRow[j] = pivotRow[j].Div(p)
+		}
+	}
+	pivotRow[s] = field.One[T]().Div(p)
+}
+
+// fusedExchange is implemented by *fraction.Fraction, which computes the
+// exchange formula in one call
+type fusedExchange[T any] interface {
+	SubMulDiv(a, b, c, p T) *T
+}
+
+// fill sets a_ij to (a_ij * p - a_is * a_rj) / p
+func fill[T field.Element[T]](aij *T, ais, arj, p T, fused bool) {
+	if fused {
+		any(aij).(fusedExchange[T]).SubMulDiv(*aij, ais, arj, p)
+		return
+	}
+	*aij = (*aij).Mul(p).Sub(ais.Mul(arj)).Div(p)
+}
@@ -0,0 +1,216 @@
+package linalg
+
+import (
+	"errors"
+	"testing"
+
+	"simplex/field"
+	fr "simplex/fraction"
+)
+
+// cofactor returns the determinant of m by cofactor expansion along the
+// first row
+func cofactor(m Matrix[fr.Fraction]) fr.Fraction {
+	n := m.Rows()
+	if n == 0 {
+		return fr.New(1, 1)
+	}
+	var det fr.Fraction
+	for j := 0; j < n; j++ {
+		minor := New[fr.Fraction](n-1, n-1)
+		for i := 1; i < n; i++ {
+			copy(minor[i-1], m[i][:j])
+			copy(minor[i-1][j:], m[i][j+1:])
+		}
+		term := fr.Mul(m[0][j], cofactor(minor))
+		if j%2 == 1 {
+			term = fr.Neg(term)
+		}
+		det = fr.Add(det, term)
+	}
+	return det
+}
+
+var matrices = []struct {
+	name string
+	m    [][]int
+	det  int
+}{
+	{"identity", [][]int{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}, 1},
+	{"2x2", [][]int{{3, 8}, {4, 6}}, -14},
+	{"3x3", [][]int{{2, 1, 1}, {1, 3, 2}, {1, 0, 0}}, -1},
+	{"needs a swap", [][]int{{0, 2, 1}, {1, 1, 1}, {2, 0, 3}}, -4},
+	{"singular", [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, 0},
+	{"4x4", [][]int{{1, 0, 2, -1}, {3, 0, 0, 5}, {2, 1, 4, -3}, {1, 0, 5, 0}}, 30},
+	{"5x5", [][]int{{2, -1, 0, 3, 1}, {1, 4, -2, 0, 5}, {0, 3, 1, -1, 2}, {4, 0, 2, 1, -3}, {-2, 1, 3, 2, 0}}, 397},
+}
+
+func TestDet(t *testing.T) {
+	for _, tt := range matrices {
+		m := FromInts[fr.Fraction](tt.m)
+		got, err := Det(m)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if want := cofactor(m); fr.Cmp(got, want) != 0 {
+			t.Errorf("%s: Det = %v, cofactor expansion gives %v", tt.name, got, want)
+		}
+		if fr.Cmp(got, fr.New(tt.det, 1)) != 0 {
+			t.Errorf("%s: Det = %v, want %d", tt.name, got, tt.det)
+		}
+	}
+
+	if _, err := Det(FromInts[fr.Fraction]([][]int{{1, 2, 3}})); !errors.Is(err, ErrDimension) {
+		t.Errorf("Det of a 1x3 matrix: err = %v, want %v", err, ErrDimension)
+	}
+}
+
+func TestDetFloat(t *testing.T) {
+	for _, tt := range matrices {
+		got, err := Det(FromInts[field.Float](tt.m))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		want := field.From[field.Float](cofactor(FromInts[fr.Fraction](tt.m)))
+		if got.Cmp(want) != 0 {
+			t.Errorf("%s: Det over Float = %v, want %v", tt.name, got, want)
+		}
+	}
+}
+
+func TestBareissStaysInteger(t *testing.T) {
+	a, _ := Bareiss(FromInts[fr.Fraction]([][]int{{2, 1, 1}, {1, 3, 2}, {1, 0, 0}}))
+	for i := range a {
+		for j := range a[i] {
+			if f := a[i][j]; f.IsBig() || f.D != 1 && f.N != 0 {
+				t.Errorf("Bareiss entry (%d, %d) = %v is not an integer", i, j, f)
+			}
+		}
+	}
+}
+
+func TestInverse(t *testing.T) {
+	m := FromInts[fr.Fraction]([][]int{{2, 1, 1}, {1, 3, 2}, {1, 0, 0}})
+	want := FromInts[fr.Fraction]([][]int{{0, 0, 1}, {-2, 1, 3}, {3, -1, -5}})
+	inv, err := Inverse(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(inv, want) {
+		t.Errorf("Inverse =\n%vwant\n%v", inv, want)
+	}
+
+	for _, tt := range matrices {
+		m := FromInts[fr.Fraction](tt.m)
+		inv, err := Inverse(m)
+		if tt.det == 0 {
+			if !errors.Is(err, ErrSingular) {
+				t.Errorf("%s: err = %v, want %v", tt.name, err, ErrSingular)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if p, _ := m.Mul(inv); !equal(p, Identity[fr.Fraction](m.Rows())) {
+			t.Errorf("%s: m * Inverse(m) =\n%v", tt.name, p)
+		}
+	}
+}
+
+func TestNullSpace(t *testing.T) {
+	tests := []struct {
+		name string
+		m    [][]int
+	}{
+		{"singular", [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}},
+		{"wide", [][]int{{1, 2, 0, -1}, {0, 0, 1, 3}}},
+		{"full rank", [][]int{{2, 1}, {1, 3}}},
+		{"zero", [][]int{{0, 0, 0}}},
+	}
+	for _, tt := range tests {
+		m := FromInts[fr.Fraction](tt.m)
+		basis := NullSpace(m)
+		if got, want := len(basis), m.Cols()-Rank(m); got != want {
+			t.Errorf("%s: %d null space vectors, want %d", tt.name, got, want)
+		}
+		for _, v := range basis {
+			p, _ := m.MulVec(v)
+			for _, x := range p {
+				if !x.IsZero() {
+					t.Errorf("%s: m * %v = %v, want 0", tt.name, v, p)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+	m := FromInts[fr.Fraction]([][]int{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}})
+	b := Vector[fr.Fraction]{fr.New(8, 1), fr.New(-11, 1), fr.New(-3, 1)}
+	x, log, err := Solve(m, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Vector[fr.Fraction]{fr.New(2, 1), fr.New(3, 1), fr.New(-1, 1)}
+	for i := range want {
+		if fr.Cmp(x[i], want[i]) != 0 {
+			t.Fatalf("Solve = %v, want %v", x, want)
+		}
+	}
+	if len(log) == 0 {
+		t.Fatal("Solve logged no row operations")
+	}
+	last := log[len(log)-1].Result
+	for i := range want {
+		if last[i][3].Cmp(want[i]) != 0 {
+			t.Errorf("last logged matrix does not hold the solution:\n%v", last)
+			break
+		}
+	}
+
+	inconsistent := FromInts[fr.Fraction]([][]int{{1, 1}, {2, 2}})
+	if _, _, err := Solve(inconsistent, Vector[fr.Fraction]{fr.New(1, 1), fr.New(3, 1)}); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("inconsistent system: err = %v, want %v", err, ErrInconsistent)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	// Exchanging every row with its column inverts the matrix
+	m := FromInts[fr.Fraction]([][]int{{2, 1, 1}, {1, 3, 2}, {1, 0, 1}})
+	e := m
+	for i := range m {
+		var err error
+		if e, err = Exchange(e, i, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inv, _ := Inverse(m); !equal(e, inv) {
+		t.Errorf("exchanges give\n%vwant\n%v", e, inv)
+	}
+	if !equal(m, FromInts[fr.Fraction]([][]int{{2, 1, 1}, {1, 3, 2}, {1, 0, 1}})) {
+		t.Error("Exchange modified its argument")
+	}
+
+	if _, err := Exchange(FromInts[fr.Fraction]([][]int{{0, 1}, {1, 0}}), 0, 0); !errors.Is(err, ErrSingular) {
+		t.Errorf("zero pivot: err = %v, want %v", err, ErrSingular)
+	}
+	if _, err := Exchange(m, 3, 0); !errors.Is(err, ErrDimension) {
+		t.Errorf("pivot outside the matrix: err = %v, want %v", err, ErrDimension)
+	}
+}
+
+func equal[T field.Element[T]](a, b Matrix[T]) bool {
+	if a.Rows() != b.Rows() || a.Cols() != b.Cols() {
+		return false
+	}
+	for i := range a {
+		for j := range a[i] {
+			if a[i][j].Cmp(b[i][j]) != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package linalg
+
+import (
+	"simplex/field"
+)
+
+// LU is the factorisation P·B = L·U of a square matrix B with partial
+// pivoting: row i of L·U is row Perm[i] of B. L has a unit diagonal and is
+// kept below the diagonal of F, U on and above it.
+type LU[T field.Element[T]] struct {
+	F    Matrix[T]
+	Perm []int
+}
+
+// Factorize returns the LU factorisation of the square matrix b by Gaussian
+// elimination, choosing the largest pivot of each column. b is not modified.
+func Factorize[T field.Element[T]](b Matrix[T]) (*LU[T], error) {
+	m := len(b)
+	f := make(Matrix[T], m)
+	perm := make([]int, m)
+	for i := range b {
+		if len(b[i]) != m {
+			return nil, ErrDimension
+		}
+		f[i] = append([]T(nil), b[i]...)
+		perm[i] = i
+	}
+
+	for k := 0; k < m; k++ {
+		p := k
+		for i := k + 1; i < m; i++ {
+			if abs(f[i][k]).Cmp(abs(f[p][k])) > 0 {
+				p = i
+			}
+		}
+		if f[p][k].IsZero() {
+			return nil, ErrSingular
+		}
+		f[k], f[p] = f[p], f[k]
+		perm[k], perm[p] = perm[p], perm[k]
+
+		for i := k + 1; i < m; i++ {
+			if f[i][k].IsZero() {
+				continue
+			}
+			l := f[i][k].Div(f[k][k])
+			f[i][k] = l
+			for j := k + 1; j < m; j++ {
+				if !f[k][j].IsZero() {
+					f[i][j] = f[i][j].Sub(l.Mul(f[k][j]))
+				}
+			}
+		}
+	}
+	return &LU[T]{F: f, Perm: perm}, nil
+}
+
+// Solve returns x with B x = b
+func (lu *LU[T]) Solve(b []T) []T {
+	m := len(lu.F)
+	x := make([]T, m)
+	for i := range x {
+		x[i] = b[lu.Perm[i]]
+	}
+	for i := 0; i < m; i++ {
+		for j := 0; j < i; j++ {
+			if !lu.F[i][j].IsZero() && !x[j].IsZero() {
+				x[i] = x[i].Sub(lu.F[i][j].Mul(x[j]))
+			}
+		}
+	}
+	for i := m - 1; i >= 0; i-- {
+		for j := i + 1; j < m; j++ {
+			if !lu.F[i][j].IsZero() && !x[j].IsZero() {
+				x[i] = x[i].Sub(lu.F[i][j].Mul(x[j]))
+			}
+		}
+		x[i] = x[i].Div(lu.F[i][i])
+	}
+	return x
+}
+
+// SolveTranspose returns y with yᵀ B = cᵀ
+func (lu *LU[T]) SolveTranspose(c []T) []T {
+	m := len(lu.F)
+	z := append([]T(nil), c...)
+	// Uᵀ z = c
+	for i := 0; i < m; i++ {
+		for j := 0; j < i; j++ {
+			if !lu.F[j][i].IsZero() && !z[j].IsZero() {
+				z[i] = z[i].Sub(lu.F[j][i].Mul(z[j]))
+			}
+		}
+		z[i] = z[i].Div(lu.F[i][i])
+	}
+	// Lᵀ v = z
+	for i := m - 1; i >= 0; i-- {
+		for j := i + 1; j < m; j++ {
+			if !lu.F[j][i].IsZero() && !z[j].IsZero() {
+				z[i] = z[i].Sub(lu.F[j][i].Mul(z[j]))
+			}
+		}
+	}
+	y := make([]T, m)
+	for i := range z {
+		y[lu.Perm[i]] = z[i]
+	}
+	return y
+}
+
+func abs[T field.Element[T]](x T) T {
+	if x.Sign() < 0 {
+		return x.Neg()
+	}
+	return x
+}
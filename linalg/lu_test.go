@@ -0,0 +1,69 @@
+package linalg
+
+import (
+	"errors"
+	"testing"
+
+	"simplex/field"
+	fr "simplex/fraction"
+)
+
+func TestLU(t *testing.T) {
+	for _, tt := range matrices {
+		m := FromInts[fr.Fraction](tt.m)
+		lu, err := Factorize(m)
+		if tt.det == 0 {
+			if !errors.Is(err, ErrSingular) {
+				t.Errorf("%s: err = %v, want %v", tt.name, err, ErrSingular)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		// L·U is m with its rows permuted by Perm
+		n := m.Rows()
+		l, u := Identity[fr.Fraction](n), New[fr.Fraction](n, n)
+		for i := range lu.F {
+			copy(l[i][:i], lu.F[i][:i])
+			copy(u[i][i:], lu.F[i][i:])
+		}
+		p, _ := l.Mul(u)
+		for i := range p {
+			if !equal(p[i:i+1], m[lu.Perm[i]:lu.Perm[i]+1]) {
+				t.Errorf("%s: row %d of L·U is %v, want row %d of m, %v", tt.name, i, p[i], lu.Perm[i], m[lu.Perm[i]])
+			}
+		}
+
+		b := make(Vector[fr.Fraction], n)
+		for i := range b {
+			b[i] = fr.New(i+1, 1)
+		}
+		x := lu.Solve(b)
+		if mx, _ := m.MulVec(x); !equal(Column(mx), Column(b)) {
+			t.Errorf("%s: Solve gives x = %v with m x = %v, want %v", tt.name, x, mx, b)
+		}
+		y := lu.SolveTranspose(b)
+		if ym, _ := m.Transpose().MulVec(y); !equal(Column(ym), Column(b)) {
+			t.Errorf("%s: SolveTranspose gives y = %v with yᵀ m = %v, want %v", tt.name, y, ym, b)
+		}
+	}
+
+	if _, err := Factorize(FromInts[fr.Fraction]([][]int{{1, 2}})); !errors.Is(err, ErrDimension) {
+		t.Errorf("Factorize of a 1x2 matrix: err = %v, want %v", err, ErrDimension)
+	}
+}
+
+func TestLUFloat(t *testing.T) {
+	m := FromInts[field.Float]([][]int{{0, 2, 1}, {1, 1, 1}, {2, 0, 3}})
+	lu, err := Factorize(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := Vector[field.Float]{1, 2, 3}
+	x := lu.Solve(b)
+	if mx, _ := m.MulVec(x); !equal(Column(mx), Column(b)) {
+		t.Errorf("Solve gives x = %v with m x = %v, want %v", x, mx, b)
+	}
+}
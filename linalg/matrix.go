@@ -0,0 +1,158 @@
+// Package linalg provides linear algebra over the number types of package
+// field. It is exact over fraction.Fraction and field.Rat.
+package linalg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"simplex/field"
+	fr "simplex/fraction"
+)
+
+var (
+	ErrDimension    = errors.New("linalg: dimension mismatch")
+	ErrSingular     = errors.New("linalg: matrix is singular")
+	ErrInconsistent = errors.New("linalg: system has no solution")
+)
+
+// Vector is a column of values
+type Vector[T field.Element[T]] []T
+
+// Matrix is stored row by row, like tableau.Tableau.Table
+type Matrix[T field.Element[T]] [][]T
+
+// New returns a rows x cols zero matrix
+func New[T field.Element[T]](rows, cols int) Matrix[T] {
+	m := make(Matrix[T], rows)
+	for i := range m {
+		m[i] = make([]T, cols)
+	}
+	return m
+}
+
+// Identity returns the n x n identity matrix
+func Identity[T field.Element[T]](n int) Matrix[T] {
+	m := New[T](n, n)
+	for i := range m {
+		m[i][i] = field.One[T]()
+	}
+	return m
+}
+
+// FromInts builds a matrix from integer rows, which must have equal length
+func FromInts[T field.Element[T]](rows [][]int) Matrix[T] {
+	m := make(Matrix[T], len(rows))
+	for i, row := range rows {
+		m[i] = make([]T, len(row))
+		for j, v := range row {
+			m[i][j] = field.From[T](fr.New(v, 1))
+		}
+	}
+	return m
+}
+
+func (m Matrix[T]) Rows() int { return len(m) }
+
+func (m Matrix[T]) Cols() int {
+	if len(m) == 0 {
+		return 0
+	}
+	return len(m[0])
+}
+
+// Copy returns a deep copy of m
+func (m Matrix[T]) Copy() Matrix[T] {
+	c := make(Matrix[T], len(m))
+	for i := range m {
+		c[i] = make([]T, len(m[i]))
+		copy(c[i], m[i])
+	}
+	return c
+}
+
+func (m Matrix[T]) Transpose() Matrix[T] {
+	t := New[T](m.Cols(), m.Rows())
+	for i := range m {
+		for j := range m[i] {
+			t[j][i] = m[i][j]
+		}
+	}
+	return t
+}
+
+// Mul returns the product m * b
+func (m Matrix[T]) Mul(b Matrix[T]) (Matrix[T], error) {
+	if m.Cols() != b.Rows() {
+		return nil, ErrDimension
+	}
+	p := New[T](m.Rows(), b.Cols())
+	for i := range p {
+		for j := range p[i] {
+			for k := range b {
+				p[i][j] = p[i][j].Add(m[i][k].Mul(b[k][j]))
+			}
+		}
+	}
+	return p, nil
+}
+
+// MulVec returns the product m * v
+func (m Matrix[T]) MulVec(v Vector[T]) (Vector[T], error) {
+	if m.Cols() != len(v) {
+		return nil, ErrDimension
+	}
+	p := make(Vector[T], m.Rows())
+	for i := range m {
+		p[i] = Dot(m[i], v)
+	}
+	return p, nil
+}
+
+// Dot returns the inner product of a and b, which must have equal length
+func Dot[T field.Element[T]](a, b Vector[T]) T {
+	var sum T
+	for i := range a {
+		sum = sum.Add(a[i].Mul(b[i]))
+	}
+	return sum
+}
+
+// Augment returns [m | b] for a matrix b with the same number of rows
+func (m Matrix[T]) Augment(b Matrix[T]) (Matrix[T], error) {
+	if m.Rows() != b.Rows() {
+		return nil, ErrDimension
+	}
+	a := make(Matrix[T], m.Rows())
+	for i := range m {
+		a[i] = append(append(make([]T, 0, m.Cols()+b.Cols()), m[i]...), b[i]...)
+	}
+	return a, nil
+}
+
+// Column returns v as an n x 1 matrix
+func Column[T field.Element[T]](v Vector[T]) Matrix[T] {
+	m := New[T](len(v), 1)
+	for i := range v {
+		m[i][0] = v[i]
+	}
+	return m
+}
+
+func (m Matrix[T]) String() string {
+	width := 0
+	for i := range m {
+		for j := range m[i] {
+			width = max(width, len(fmt.Sprintf("% v", m[i][j])))
+		}
+	}
+	var sb strings.Builder
+	for i := range m {
+		for j := range m[i] {
+			fmt.Fprintf(&sb, "% -*v", width+2, m[i][j])
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -2,16 +2,37 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	
+	"simplex/field"
 	fr "simplex/fraction"
 	"simplex/parser"
+	"simplex/simplex"
 	tb "simplex/tableau"
 )
 
 func main() {
+	bigM := flag.Bool("bigm", false, "use the Big-M method instead of two-phase for >= and = constraints")
+	ruleName := flag.String("rule", "dantzig", "pivot rule: "+strings.Join(tb.RuleNames, ", "))
+	seed := flag.Int64("seed", 1, "seed of the random pivot rule")
+	arithName := flag.String("arith", "fraction", "arithmetic: "+strings.Join(simplex.ArithmeticNames, ", "))
+	step := flag.Bool("step", false, "solve quietly, then walk through the recorded pivots one at a time")
+	maxIter := flag.Int("maxiter", 0, "iteration limit over all phases, 0 for the default of 1000")
+	rational := flag.Bool("rational", false, "with -arith float, print results as the simplest fractions within the float tolerance")
+	flag.Parse()
+
+	arithmetic, err := simplex.ParseArithmetic(*arithName)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 	var problemType string
 
@@ -30,7 +51,7 @@ func main() {
 
 	fmt.Print("Enter the number of constraints: ")
 	var constraintCount int
-	fmt.Scan(&constraintCount)
+	fmt.Fscan(reader, &constraintCount)
 	reader.ReadString('\n') // Consume newline
 
 	constraintStrs := make([]string, constraintCount)
@@ -53,75 +74,170 @@ func main() {
 		fmt.Printf("Constraint %d: %s\n", i+1, constraint)
 	}
 
-	// Convert the problem to tableau format
-	st := parser.ConvertToTableau(problem)
+	opts := simplex.Options{
+		Rule:       *ruleName,
+		Seed:       *seed,
+		BigM:       *bigM,
+		MaxIter:    *maxIter,
+		Arithmetic: arithmetic,
+		Tracer:     tb.ConsoleTracer{W: os.Stdout},
+	}
+
+	if *step {
+		switch arithmetic {
+		case simplex.Fraction:
+			stepThrough[fr.Fraction](reader, problem, opts)
+		case simplex.Rat:
+			stepThrough[field.Rat](reader, problem, opts)
+		case simplex.Float:
+			stepThrough[field.Float](reader, problem, opts)
+		}
+		return
+	}
+
+	solution, err := simplex.Solve(context.Background(), problem, opts)
+	switch {
+	case errors.Is(err, tb.ErrInfeasible):
+		fmt.Println("No feasible solution exists:", err)
+		return
+	case errors.Is(err, tb.ErrUnbounded):
+		fmt.Println("No valid pivot found:", err)
+		return
+	case errors.Is(err, tb.ErrIterationLimit):
+		fmt.Println("Warning:", err)
+		return
+	case err != nil:
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	fmt.Println("\nBasis:", solution.Basis)
+	fmt.Printf("Optimal solution reached after %d iterations with the %s rule!\n", solution.Iterations, *ruleName)
+
+	// Float results are shown as fractions on request, e.g. 1/3 for 0.333333
+	num := "%v"
+	if *rational && arithmetic == simplex.Float {
+		num = "%r"
+	}
 
-	fmt.Println("\nInitial Tableau:")
-	tb.Print(&st)
+	fmt.Println("\nSolution:")
+	vars := make([]string, 0, len(solution.Values))
+	for v := range solution.Values {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+	for _, v := range vars {
+		fmt.Printf("%s = "+num+"\n", v, solution.Values[v])
+	}
+	for i, slack := range solution.Slacks {
+		fmt.Printf("Slack of %v = "+num+"\n", problem.Constraints[i], slack)
+	}
+	for i, slack := range solution.BoundSlacks {
+		fmt.Printf("Slack of %v = "+num+"\n", problem.BoundSources[i], slack)
+	}
 	
-	if !st.IsFeasible() {
-		fmt.Println("Warning: Initial tableau is not feasible (contains negative RHS values)")
+	// Print objective value
+	fmt.Print("\nObjective value = ")
+	fmt.Printf(num+"\n", solution.Objective)
+}
 
-		if !st.MakeFeasible() {
-			fmt.Println("Failed to find feasible solution. Problem may be infeasible.")
-			return
+// stepThrough solves problem quietly while recording its pivots, then lets
+// the user walk through them
+func stepThrough[T field.Element[T]](reader *bufio.Reader, problem *parser.Problem, opts simplex.Options) {
+	t := parser.ConvertToTableau[T](problem)
+	history := tb.NewHistory(&t)
+	opts.Tracer = nil
+	if _, err := simplex.Run(context.Background(), &t, opts); err != nil && history.Err == nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	if history.Status == tb.Failed {
+		fmt.Printf("\nRecorded %d steps before the run failed: %v\n", len(history.Entries), history.Err)
+	} else {
+		fmt.Printf("\nRecorded %d steps, the problem is %v.\n", len(history.Entries), history.Status)
+	}
+	fmt.Println("Commands: n (next), p (previous), r (replay), e FILE (export as JSON), q (quit)")
+	show := func() {
+		fmt.Printf("\nStep %d of %d\n", history.Position(), len(history.Entries))
+		tb.Print(history.Current())
+	}
+	describe := func(step tb.Step) string {
+		if tb.IsPhaseChange(step.Row, step.Col) {
+			return fmt.Sprintf("%s starts", step.Phase)
 		}
+		if tb.IsFlip(step.Row, step.Col) {
+			return fmt.Sprintf("%s iteration %d: %s moves to its other bound", step.Phase, step.Iteration, step.Entering)
+		}
+		return fmt.Sprintf("%s iteration %d: %s enters, %s leaves", step.Phase, step.Iteration, step.Entering, step.Leaving)
 	}
-	
-	iteration := 1
+	show()
+
 	for {
-		if st.IsOptimal() {
-			fmt.Println("Optimal solution reached!")
-			break
-		}
-		
-		fmt.Printf("\n--- Iteration %d ---\n", iteration)
-		r, s := st.Pivot()
-		if !tb.IsPivotValid(r, s) { 
-			fmt.Println("No valid pivot found. Solution may be unbounded.")
-			break 
+		fmt.Print("> ")
+		line, err := reader.ReadString('\n')
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			if err != nil {
+				return
+			}
+			fields = []string{"n"}
 		}
 
-		fmt.Printf("Pivoting on element at row %d, column %d (intersection of %s and %s)\n", 
-				r, s, st.RowNames[r], st.ColNames[s])
-		b := st.Transform(r, s)
-		st = b.Copy()
-		
-		tb.Print(&st)
-		iteration++
-		
-		// Safety check to prevent infinite loops
-		if iteration > 100 {
-			fmt.Println("Warning: Maximum iterations reached. Process stopped.")
-			break
+		switch fields[0] {
+		case "n":
+			e, ok := history.Forward()
+			if !ok {
+				fmt.Println("End of the run:", history.Status)
+				continue
+			}
+			fmt.Println(describe(e.Step))
+			show()
+		case "p":
+			e, ok := history.Back()
+			if !ok {
+				fmt.Println("Start of the run")
+				continue
+			}
+			fmt.Println("Undid", describe(e.Step))
+			show()
+		case "r":
+			err := history.Replay(func(e tb.Entry[T], t *tb.Tableau[T]) {
+				fmt.Printf("\n%s\n", describe(e.Step))
+				tb.Print(t)
+			})
+			if err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Println("\nThe replay matches the recording.")
+			}
+		case "e":
+			if len(fields) < 2 {
+				fmt.Println("Usage: e FILE")
+				continue
+			}
+			if err := export(history, fields[1]); err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Println("Exported to", fields[1])
+			}
+		case "q":
+			return
+		default:
+			fmt.Println("Commands: n (next), p (previous), r (replay), e FILE (export as JSON), q (quit)")
 		}
 	}
+}
 
-	// Print final solution
-	fmt.Println("\nFinal Tableau:")
-	tb.Print(&st)
-	
-	solution := st.GetSolution()
-	fmt.Println("\nSolution:")
-	
-	// Print variable values (focus on the original decision variables)
-	for varName, value := range solution {
-		// Handle both possible tableau formats (variable as row or column)
-		cleanName := strings.TrimPrefix(varName, "-")
-		if varName != "objective" && !strings.HasPrefix(varName, "s") {
-			fmt.Printf("%s = ", cleanName)
-			fr.Print(&value, 0)
-			fmt.Println()
-		}
+// export writes history to the file name as JSON
+func export[T field.Element[T]](history *tb.History[T], name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
 	}
-	
-	// Print objective value
-	fmt.Print("\nObjective value = ")
-	objectiveValue := solution["objective"] 
-	if !isMaximization {
-		// For minimization problems, we typically negate the final objective value
-		objectiveValue = fr.Neg(objectiveValue)
+	if err := history.WriteJSON(f); err != nil {
+		f.Close()
+		return err
 	}
-	fr.Print(&objectiveValue, 0)
-	fmt.Println()
+	return f.Close()
 }
@@ -6,9 +6,9 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 
+	"simplex/field"
 	fr "simplex/fraction"
 	tb "simplex/tableau"
 )
@@ -26,12 +26,26 @@ type Equation struct {
 	Relation string // "<=", ">=", "="
 }
 
+// Bound is the range Lower <= x <= Upper of a variable. It is empty when
+// Lower is above Upper, and then the problem is infeasible.
+type Bound struct {
+	Lower, Upper field.Ext[fr.Fraction]
+}
+
+// DefaultBound is the bound 0 <= x of a variable without an entry in
+// Problem.Bounds
+func DefaultBound() Bound {
+	return Bound{Lower: field.Finite(fr.New(0, 1)), Upper: field.Inf[fr.Fraction](1)}
+}
+
 // Problem represents a complete linear programming problem
 type Problem struct {
 	ObjectiveFunction Equation
 	Constraints       []Equation
 	IsMaximization    bool
-	Variables         map[string]bool // Set of all variables
+	Variables         map[string]bool  // Set of all variables
+	Bounds            map[string]Bound // Bounds other than the default one
+	BoundSources      []Equation       // Constraints on a single variable that became Bounds, in input order
 }
 
 // ParseProblem parses a complete linear programming problem
@@ -40,6 +54,7 @@ func ParseProblem(objectiveStr string, constraintStrs []string, isMax bool) (*Pr
 		IsMaximization: isMax,
 		Constraints:    make([]Equation, 0, len(constraintStrs)),
 		Variables:      make(map[string]bool),
+		Bounds:         make(map[string]Bound),
 	}
 
 	// Parse objective function (format: "3x1 + 2x2 + ... + 5xn")
@@ -56,8 +71,22 @@ func ParseProblem(objectiveStr string, constraintStrs []string, isMax bool) (*Pr
 		}
 	}
 
-	// Parse constraints
+	// Parse constraints. Sign declarations lift the default x >= 0 before
+	// the bounds apply, wherever they appear
+	var bounds []Equation
 	for i, constraintStr := range constraintStrs {
+		// A free variable is declared as "x3 free"
+		if v, ok := parseFree(constraintStr); ok {
+			problem.Variables[v] = true
+			problem.Bounds[v] = Bound{Lower: field.Inf[fr.Fraction](-1), Upper: problem.Bound(v).Upper}
+			continue
+		}
+
+		// "x4 <= 0" declares x4 non-positive, and is also its bound below
+		if v, ok := parseNonPositive(constraintStr); ok {
+			problem.Bounds[v] = Bound{Lower: field.Inf[fr.Fraction](-1), Upper: problem.Bound(v).Upper}
+		}
+
 		// First find the relation
 		relation := ""
 		for _, rel := range []string{"<=", ">=", "="} {
@@ -74,7 +103,6 @@ func ParseProblem(objectiveStr string, constraintStrs []string, isMax bool) (*Pr
 		if err != nil {
 			return nil, fmt.Errorf("error parsing constraint %d: %w", i+1, err)
 		}
-		problem.Constraints = append(problem.Constraints, constraint)
 
 		// Add variables from constraints
 		for _, term := range constraint.LHS {
@@ -82,11 +110,162 @@ func ParseProblem(objectiveStr string, constraintStrs []string, isMax bool) (*Pr
 				problem.Variables[term.Variable] = true
 			}
 		}
+
+		// A constraint on a single variable, like "x1 <= 10", becomes a bound
+		if _, _, _, ok := single(constraint); !ok {
+			problem.Constraints = append(problem.Constraints, constraint)
+			continue
+		}
+		bounds = append(bounds, constraint)
+	}
+
+	for _, eq := range bounds {
+		problem.addBound(eq)
 	}
+	problem.BoundSources = bounds
 
 	return problem, nil
 }
 
+// parseFree returns the variable of a declaration like "x3 free"
+func parseFree(s string) (string, bool) {
+	m := regexp.MustCompile(`^\s*(x\d+|[a-zA-Z]\d*)\s+free\s*$`).FindStringSubmatch(s)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// parseNonPositive returns the variable of a declaration like "x4 <= 0"
+func parseNonPositive(s string) (string, bool) {
+	m := regexp.MustCompile(`^\s*(x\d+|[a-zA-Z]\d*)\s*<=\s*0\s*$`).FindStringSubmatch(s)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// Bound returns the bound of variable v
+func (p *Problem) Bound(v string) Bound {
+	if b, ok := p.Bounds[v]; ok {
+		return b
+	}
+	return DefaultBound()
+}
+
+// BoundConstraints returns the bounds that differ from the default one as
+// constraints, for solvers without native support for bounds. Lower bounds
+// below zero need Standard first.
+func (p *Problem) BoundConstraints() []Equation {
+	vars := make([]string, 0, len(p.Bounds))
+	for v := range p.Bounds {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+
+	var eqs []Equation
+	for _, v := range vars {
+		b := p.Bounds[v]
+		x := []Term{{Coefficient: fr.New(1, 1), Variable: v}}
+		if l, ok := b.Lower.Value(); ok && l.Sign() > 0 {
+			eqs = append(eqs, Equation{LHS: x, RHS: l, Relation: ">="})
+		}
+		if u, ok := b.Upper.Value(); ok {
+			eqs = append(eqs, Equation{LHS: x, RHS: u, Relation: "<="})
+		}
+	}
+	return eqs
+}
+
+// single returns eq as "v relation x" if eq constrains a single variable
+func single(eq Equation) (v string, x fr.Fraction, relation string, ok bool) {
+	eq = eq.Normalized()
+	if len(eq.LHS) != 1 || eq.LHS[0].Coefficient.IsZero() {
+		return "", fr.Fraction{}, "", false
+	}
+
+	relation = eq.Relation
+	if eq.LHS[0].Coefficient.Sign() < 0 {
+		relation = flip(relation)
+	}
+	return eq.LHS[0].Variable, fr.Div(eq.RHS, eq.LHS[0].Coefficient), relation, true
+}
+
+// addBound narrows the bound of the variable of eq, which constrains a
+// single variable. The bound may become empty.
+func (p *Problem) addBound(eq Equation) {
+	v, value, relation, _ := single(eq)
+	x := field.Finite(value)
+
+	b := p.Bound(v)
+	if relation != ">=" && x.Less(b.Upper) {
+		b.Upper = x
+	}
+	if relation != "<=" && b.Lower.Less(x) {
+		b.Lower = x
+	}
+	p.Bounds[v] = b
+}
+
+// Substitution gives a variable as Constant plus the sum of Terms, over
+// variables that are non-negative
+type Substitution struct {
+	Constant fr.Fraction
+	Terms    []Term
+}
+
+// Standard returns a copy of p in which every variable is non-negative,
+// together with the substitution of each variable it replaced. A variable x
+// with a lower bound l other than zero becomes l + x, keeping its name; one
+// with only an upper bound u becomes u - x-, so a non-positive variable is
+// -x-; and a free one becomes x+ - x-. Bounds of the copy keep the upper
+// bounds of shifted variables. An empty bound becomes a constraint x <= u - l
+// at the end of the constraints of the copy, which no x >= 0 can meet.
+func (p *Problem) Standard() (*Problem, map[string]Substitution) {
+	s := &Problem{
+		IsMaximization: p.IsMaximization,
+		Variables:      make(map[string]bool),
+		Bounds:         make(map[string]Bound),
+	}
+	subs := make(map[string]Substitution)
+	one := fr.New(1, 1)
+	var empty []Equation
+
+	for v := range p.Variables {
+		b := p.Bound(v)
+		l, finite := b.Lower.Value()
+		u, bounded := b.Upper.Value()
+		switch {
+		case finite:
+			s.Variables[v] = true
+			if bounded && fr.Cmp(u, l) < 0 {
+				empty = append(empty, Equation{LHS: []Term{{Coefficient: one, Variable: v}}, RHS: fr.Sub(u, l), Relation: "<="})
+			} else if bounded {
+				s.Bounds[v] = Bound{Lower: field.Finite(fr.New(0, 1)), Upper: field.Finite(fr.Sub(u, l))}
+			}
+			if !l.IsZero() {
+				subs[v] = Substitution{Constant: l, Terms: []Term{{Coefficient: one, Variable: v}}}
+			}
+		case bounded:
+			s.Variables[v+"-"] = true
+			subs[v] = Substitution{Constant: u, Terms: []Term{{Coefficient: fr.Neg(one), Variable: v + "-"}}}
+		default:
+			s.Variables[v+"+"] = true
+			s.Variables[v+"-"] = true
+			subs[v] = Substitution{Terms: []Term{{Coefficient: one, Variable: v + "+"}, {Coefficient: fr.Neg(one), Variable: v + "-"}}}
+		}
+	}
+
+	s.ObjectiveFunction = substitute(p.ObjectiveFunction, subs)
+	s.Constraints = make([]Equation, len(p.Constraints))
+	for i, constraint := range p.Constraints {
+		s.Constraints[i] = substitute(constraint, subs)
+	}
+	sort.Slice(empty, func(i, j int) bool { return empty[i].LHS[0].Variable < empty[j].LHS[0].Variable })
+	s.Constraints = append(s.Constraints, empty...)
+	return s, subs
+}
+
 // ParseEquation parses a single equation or inequality
 func parseEquation(eqStr, relation string) (Equation, error) {
 	parts := strings.SplitN(eqStr, relation, 2)
@@ -123,8 +302,14 @@ func parseEquation(eqStr, relation string) (Equation, error) {
 
 // ParseTerms parses the terms in the left-hand side of an equation
 func parseTerms(lhsStr string) ([]Term, error) {
+	// Protect characters that must not split terms: the space inside a mixed
+	// number ("2 1/3x1") and the sign of an exponent ("1.5e-3x1")
+	lhsStr = regexp.MustCompile(`(^|[\+\-\s])(\d+)\s+(\d+/\d+)`).ReplaceAllString(lhsStr, "${1}${2}_${3}")
+	lhsStr = regexp.MustCompile(`(\d\.?[eE])\-(\d)`).ReplaceAllString(lhsStr, "${1}~${2}")
+	lhsStr = regexp.MustCompile(`(\d\.?[eE])\+(\d)`).ReplaceAllString(lhsStr, "${1}^${2}")
+
 	// Add + between terms if there's no operator
-	re := regexp.MustCompile(`([^\+\-\s])(\s+)(-?\d)`)
+	re := regexp.MustCompile(`([^\+\-\s])(\s+)(-?[\d.])`)
 	lhsStr = re.ReplaceAllString(lhsStr, "$1 + $3")
 
 	// Add + at the beginning if the expression starts with a variable
@@ -161,49 +346,42 @@ func parseTerms(lhsStr string) ([]Term, error) {
 		}
 
 		// Get the sign from the operator
-		sign := 1
+		sign := fr.Fraction{N: 1, D: 1}
 		if operators[i] == "-" {
-			sign = -1
+			sign = fr.Fraction{N: -1, D: 1}
 		}
 
 		// Split coefficient and variable
 		var coef fr.Fraction
 		var variable string
 
-		// Check if component is just a number
-		if _, err := strconv.Atoi(component); err == nil {
-			coef = fr.Fraction{N: sign * mustAtoi(component), D: 1}
-			variable = "" // No variable associated
-		} else {
-			// Match pattern like "2x1", "x2", etc.
-			re = regexp.MustCompile(`^(\d*)(x\d+|[a-zA-Z]\d*)$`)
-			matches := re.FindStringSubmatch(component)
-
-			if len(matches) == 3 {
-				coefStr := matches[1]
-				variable = matches[2]
-
-				if coefStr == "" {
-					// If no coefficient is specified, it's 1
-					coef = fr.Fraction{N: sign * 1, D: 1}
-				} else {
-					coef = fr.Fraction{N: sign * mustAtoi(coefStr), D: 1}
-				}
+		// A component that is a whole number literal is a constant, so
+		// "1.5e2" is 150 and not 1.5 times a variable e2
+		if num, err := fr.Parse(unprotect(component)); err == nil {
+			terms = append(terms, Term{Coefficient: fr.Mul(sign, num)})
+			continue
+		}
+
+		// Match pattern like "2x1", "x2", "1/2x1", "0.25x1", "1.5e2x1" or "2 1/3x1"
+		re = regexp.MustCompile(`^([\d./_~^eE]*?)(x\d+|[a-zA-Z]\d*)$`)
+		matches := re.FindStringSubmatch(component)
+
+		if len(matches) == 3 {
+			coefStr := matches[1]
+			variable = matches[2]
+
+			if coefStr == "" {
+				// If no coefficient is specified, it's 1
+				coef = sign
 			} else {
-				// Check for fractions like "1/2x1"
-				re = regexp.MustCompile(`^(\d+)/(\d+)(x\d+|[a-zA-Z]\d*)$`)
-				matches = re.FindStringSubmatch(component)
-
-				if len(matches) == 4 {
-					num := sign * mustAtoi(matches[1])
-					den := mustAtoi(matches[2])
-					variable = matches[3]
-					coef = fr.Fraction{N: num, D: den}
-					coef.Simplify()
-				} else {
-					return nil, fmt.Errorf("invalid term format: %s", component)
+				num, err := fr.Parse(unprotect(coefStr))
+				if err != nil {
+					return nil, fmt.Errorf("invalid term format: %s", unprotect(component))
 				}
+				coef = fr.Mul(sign, num)
 			}
+		} else {
+			return nil, fmt.Errorf("invalid term format: %s", unprotect(component))
 		}
 
 		terms = append(terms, Term{Coefficient: coef, Variable: variable})
@@ -219,28 +397,24 @@ func parseFraction(s string) (fr.Fraction, error) {
 		return fr.Fraction{N: 0, D: 1}, nil
 	}
 
-	// Check if it's a simple integer
-	if i, err := strconv.Atoi(s); err == nil {
-		return fr.Fraction{N: i, D: 1}, nil
-	}
-
-	// Check if it's a fraction like "1/2"
-	parts := strings.Split(s, "/")
-	if len(parts) == 2 {
-		num, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
-		den, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
-		if err1 == nil && err2 == nil && den != 0 {
-			frac := fr.Fraction{N: num, D: den}
-			frac.Simplify()
-			return frac, nil
-		}
+	frac, err := fr.Parse(s)
+	if err != nil {
+		return fr.Fraction{}, fmt.Errorf("invalid number format: %s", s)
 	}
-
-	return fr.Fraction{}, fmt.Errorf("invalid number format: %s", s)
+	return frac, nil
 }
 
-// ConvertToTableau converts a Problem to a Tableau in standard form for simplex method
-func ConvertToTableau(p *Problem) tb.Tableau {
+// ConvertToTableau converts a Problem to a Tableau in standard form for simplex method.
+// T selects the arithmetic, e.g. ConvertToTableau[fr.Fraction] or ConvertToTableau[field.Float].
+// Rows with a negative right-hand side are negated first. A "<=" row gets a
+// slack variable, a ">=" row a surplus column and an artificial variable, and
+// an "=" row an artificial variable. If there are artificial variables the
+// tableau starts in Phase I, see Tableau.StartPhaseOne. Bounds take no rows: the
+// variables are made non-negative as in Problem.Standard, upper bounds go to
+// Tableau.SetUpper, and GetSolution maps the values back.
+func ConvertToTableau[T field.Element[T]](original *Problem) tb.Tableau[T] {
+	p, subs := original.Standard()
+
 	// Extract all decision variables from the problem
 	decisionVars := make([]string, 0, len(p.Variables))
 	for v := range p.Variables {
@@ -249,96 +423,257 @@ func ConvertToTableau(p *Problem) tb.Tableau {
 	// Sort variables for consistent ordering
 	sort.Strings(decisionVars)
 
+	// Bring each constraint to a non-negative right-hand side
+	constraints := make([]Equation, len(p.Constraints))
+	for i, constraint := range p.Constraints {
+		constraints[i] = constraint.Normalized()
+	}
+
+	// Surplus columns for ">=" rows follow the decision variables
+	surplus := make(map[int]int)
+	for i, constraint := range constraints {
+		if constraint.Relation == ">=" {
+			surplus[i] = len(decisionVars) + len(surplus)
+		}
+	}
+
 	// Create a tableau with the appropriate dimensions
 	// Rows: one for each constraint plus objective function
-	// Columns: one for each decision variable plus RHS
-	numRows := len(p.Constraints) + 1
-	numCols := len(decisionVars) + 1 // +1 for RHS
-	var t tb.Tableau
+	// Columns: one for each decision and surplus variable plus RHS
+	numRows := len(constraints) + 1
+	numCols := len(decisionVars) + len(surplus) + 1 // +1 for RHS
+	var t tb.Tableau[T]
 	t.Init(numRows, numCols)
 	t.SetMaximization(p.IsMaximization)
 
 	// Set up column names (decision variables)
 	for i, v := range decisionVars {
-		// In standard simplex tableau, we use negative of variables
-		t.ColNames[i] = "-" + v
+		t.ColNames[i] = v
+	}
+	for i, j := range surplus {
+		t.ColNames[j] = FreshName(original, "e", i+1)
 	}
 	t.ColNames[numCols-1] = "const" // Last column is constants
 
-	// Set up row names (slack variables)
-	for i := 0; i < len(p.Constraints); i++ {
-		t.RowNames[i] = fmt.Sprintf("s%d", i+1)
+	// Set up row names (slack variables, artificial variables for ">=" and "=")
+	var artificial []string
+	for i, constraint := range constraints {
+		if constraint.Relation == "<=" {
+			t.RowNames[i] = FreshName(original, "s", i+1)
+		} else {
+			t.RowNames[i] = FreshName(original, "a", i+1)
+			artificial = append(artificial, t.RowNames[i])
+		}
 	}
 	t.RowNames[numRows-1] = "F" // Last row is objective function
 
 	// Fill in constraint rows
-	for i, constraint := range p.Constraints {
-		// Initialize RHS with constraint's RHS
-		t.Table[i][numCols-1] = constraint.RHS
+	for i, constraint := range constraints {
+		t.Table[i][numCols-1] = field.From[T](constraint.RHS)
 
-		// Add coefficients for decision variables with proper signs
+		// Add coefficients for decision variables
 		for _, term := range constraint.LHS {
 			if term.Variable != "" {
 				// Find corresponding column
 				for j, v := range decisionVars {
 					if v == term.Variable {
-						// For standard form, we move all variables to RHS with negated coefficients
-						// But in tableau, we keep the original sign for computational purposes
-						t.Table[i][j] = term.Coefficient
+						t.Table[i][j] = field.From[T](term.Coefficient)
 						break
 					}
 				}
-			} else {
-				// Constant term is handled by adjusting RHS
-				t.Table[i][numCols-1] = fr.Sub(t.Table[i][numCols-1], term.Coefficient)
 			}
 		}
 
-		// Handle inequality relations
-		if constraint.Relation == ">=" {
-			// For >= constraint, negate entire row to make it <= form
-			for j := 0; j < numCols; j++ {
-				t.Table[i][j] = fr.Neg(t.Table[i][j])
-			}
+		// The artificial variable of a ">=" row is a = b - (a*x - e)
+		if j, ok := surplus[i]; ok {
+			t.Table[i][j] = field.From[T](fr.New(-1, 1))
 		}
-		// For = constraints, we keep them as is
 	}
 
-	// Fill in objective function row
+	// Fill in objective function row. F is the objective itself for both
+	// directions; IsMaximization only selects the pivot rule
 	objRow := numRows - 1
 	for _, term := range p.ObjectiveFunction.LHS {
 		if term.Variable != "" {
 			// Find corresponding column
 			for j, v := range decisionVars {
 				if v == term.Variable {
-					if p.IsMaximization {
-						// For maximization, we put negative coefficients in objective row
-						t.Table[objRow][j] = fr.Neg(term.Coefficient)
-					} else {
-						// For minimization, coefficient signs remain unchanged
-						t.Table[objRow][j] = term.Coefficient
-					}
+					t.Table[objRow][j] = field.From[T](term.Coefficient).Neg()
 					break
 				}
 			}
 		} else {
 			// Constant term goes to RHS
-			if p.IsMaximization {
-				t.Table[objRow][numCols-1] = fr.Add(t.Table[objRow][numCols-1], term.Coefficient)
-			} else {
-				t.Table[objRow][numCols-1] = fr.Sub(t.Table[objRow][numCols-1], term.Coefficient)
-			}
+			t.Table[objRow][numCols-1] = t.Table[objRow][numCols-1].Add(field.From[T](term.Coefficient))
+		}
+	}
+
+	// Upper bounds are handled by the bounded simplex in Tableau.Pivot
+	for _, v := range decisionVars {
+		if u, ok := p.Bound(v).Upper.Value(); ok {
+			t.SetUpper(v, field.From[T](u))
+		}
+	}
+	for v, sub := range subs {
+		terms := make(map[string]T, len(sub.Terms))
+		for _, term := range sub.Terms {
+			terms[term.Variable] = field.From[T](term.Coefficient)
+		}
+		t.Recover(v, field.From[T](sub.Constant), terms)
+	}
+
+	// A row negated by Normalized swaps slack and surplus, so either one is
+	// the slack of the original constraint
+	variables := make([]string, 0, len(original.Variables))
+	for v := range original.Variables {
+		variables = append(variables, v)
+	}
+	sort.Strings(variables)
+	slacks := make([]string, len(original.Constraints))
+	for i, constraint := range constraints[:len(slacks)] {
+		switch constraint.Relation {
+		case "<=":
+			slacks[i] = t.RowNames[i]
+		case ">=":
+			slacks[i] = t.ColNames[surplus[i]]
 		}
 	}
+	t.SetOrigin(variables, slacks)
+
+	if len(artificial) > 0 {
+		t.StartPhaseOne(artificial)
+	}
 
 	return t
 }
 
-// Helper function to convert string to int, panics on error
-func mustAtoi(s string) int {
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		panic(fmt.Sprintf("mustAtoi: cannot convert %s to int", s))
+// Normalized returns the equation with like terms collected, constant terms
+// moved to the right-hand side, and both sides negated if that side is
+// negative, flipping the relation
+func (eq Equation) Normalized() Equation {
+	var lhs []Term
+	col := make(map[string]int)
+	rhs := eq.RHS
+	for _, term := range eq.LHS {
+		if term.Variable == "" {
+			// Constant term is handled by adjusting RHS
+			rhs = fr.Sub(rhs, term.Coefficient)
+		} else if j, ok := col[term.Variable]; ok {
+			lhs[j].Coefficient = fr.Add(lhs[j].Coefficient, term.Coefficient)
+		} else {
+			col[term.Variable] = len(lhs)
+			lhs = append(lhs, term)
+		}
 	}
-	return i
+
+	norm := Equation{LHS: lhs, RHS: rhs, Relation: eq.Relation}
+	if rhs.Sign() < 0 {
+		norm = negate(norm)
+	}
+	return norm
+}
+
+// Slack returns b - a·x for a "<=" equation, a·x - b for a ">=" one and zero
+// for "=", at the given values of its variables
+func Slack[T field.Element[T]](eq Equation, values map[string]T) T {
+	var ax T
+	for _, term := range eq.LHS {
+		c := field.From[T](term.Coefficient)
+		if term.Variable != "" {
+			c = c.Mul(values[term.Variable])
+		}
+		ax = ax.Add(c)
+	}
+	b := field.From[T](eq.RHS)
+	switch eq.Relation {
+	case "<=":
+		return b.Sub(ax)
+	case ">=":
+		return ax.Sub(b)
+	}
+	var zero T
+	return zero
+}
+
+// String returns eq in the form ParseProblem reads, like "3x1 - 2x2 <= 6"
+func (eq Equation) String() string {
+	var b strings.Builder
+	for i, term := range eq.LHS {
+		c := term.Coefficient
+		switch {
+		case i == 0 && c.Sign() < 0:
+			b.WriteString("-")
+		case i > 0 && c.Sign() < 0:
+			b.WriteString(" - ")
+		case i > 0:
+			b.WriteString(" + ")
+		}
+		if c.Sign() < 0 {
+			c = fr.Neg(c)
+		}
+		if term.Variable == "" || fr.Cmp(c, fr.New(1, 1)) != 0 {
+			b.WriteString(c.String())
+		}
+		b.WriteString(term.Variable)
+	}
+	if len(eq.LHS) == 0 {
+		b.WriteString("0")
+	}
+	fmt.Fprintf(&b, " %s %v", eq.Relation, eq.RHS)
+	return b.String()
+}
+
+// negate multiplies both sides of an equation by -1, flipping the relation
+func negate(eq Equation) Equation {
+	lhs := make([]Term, len(eq.LHS))
+	for i, term := range eq.LHS {
+		lhs[i] = Term{Coefficient: fr.Neg(term.Coefficient), Variable: term.Variable}
+	}
+	return Equation{LHS: lhs, RHS: fr.Neg(eq.RHS), Relation: flip(eq.Relation)}
+}
+
+// flip returns the relation with its sides swapped
+func flip(relation string) string {
+	switch relation {
+	case "<=":
+		return ">="
+	case ">=":
+		return "<="
+	}
+	return relation
+}
+
+// substitute replaces the variables of eq that have a substitution in subs,
+// leaving the constants it brings as constant terms
+func substitute(eq Equation, subs map[string]Substitution) Equation {
+	lhs := make([]Term, 0, len(eq.LHS))
+	for _, term := range eq.LHS {
+		sub, ok := subs[term.Variable]
+		if !ok {
+			lhs = append(lhs, term)
+			continue
+		}
+		if !sub.Constant.IsZero() {
+			lhs = append(lhs, Term{Coefficient: fr.Mul(term.Coefficient, sub.Constant)})
+		}
+		for _, t := range sub.Terms {
+			lhs = append(lhs, Term{Coefficient: fr.Mul(term.Coefficient, t.Coefficient), Variable: t.Variable})
+		}
+	}
+	return Equation{LHS: lhs, RHS: eq.RHS, Relation: eq.Relation}
+}
+
+// freshName returns prefix followed by i, primed until it does not clash
+// with a variable of the problem
+func FreshName(p *Problem, prefix string, i int) string {
+	name := fmt.Sprintf("%s%d", prefix, i)
+	for p.Variables[name] {
+		name += "'"
+	}
+	return name
+}
+
+// unprotect restores the characters hidden from the term splitter in parseTerms
+func unprotect(s string) string {
+	return strings.NewReplacer("_", " ", "~", "-", "^", "+").Replace(s)
 }
@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"testing"
+
+	fr "simplex/fraction"
+)
+
+func TestBounds(t *testing.T) {
+	inf := "∞"
+	tests := []struct {
+		name         string
+		constraints  []string
+		v            string
+		lower, upper string
+		rows         int // Constraints left after the bounds are taken out
+	}{
+		{"upper", []string{"x1 <= 10", "x1 + x2 <= 4"}, "x1", "0", "10", 1},
+		{"lower", []string{"x2 >= 1/2", "x1 + x2 <= 4"}, "x2", "1/2", inf, 1},
+		{"scaled", []string{"2x1 <= 6"}, "x1", "0", "3", 0},
+		{"negated", []string{"-x1 <= -2"}, "x1", "2", inf, 0},
+		{"constant term", []string{"x1 + 5 <= 5"}, "x1", "0", "0", 0},
+		{"equality", []string{"x1 = 3/2"}, "x1", "3/2", "3/2", 0},
+		{"tightest", []string{"x1 <= 5", "x1 <= 3", "x1 >= 1", "x1 >= 2"}, "x1", "2", "3", 0},
+		{"empty", []string{"x1 >= 3", "x1 <= 1"}, "x1", "3", "1", 0},
+		{"free", []string{"x3 free", "x1 + x3 >= -2"}, "x3", "-∞", inf, 1},
+		{"free after an upper bound", []string{"x3 <= 4", "x3 free"}, "x3", "-∞", "4", 0},
+		{"non-positive", []string{"x4 <= 0", "x1 + x4 <= 4"}, "x4", "-∞", "0", 1},
+		{"non-positive with a lower bound", []string{"x4 >= -2", "x4 <= 0"}, "x4", "-2", "0", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseProblem("x1", tt.constraints, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := p.Bound(tt.v)
+			if got := b.Lower.String(); got != tt.lower {
+				t.Errorf("lower bound of %s = %s, want %s", tt.v, got, tt.lower)
+			}
+			if got := b.Upper.String(); got != tt.upper {
+				t.Errorf("upper bound of %s = %s, want %s", tt.v, got, tt.upper)
+			}
+			if len(p.Constraints) != tt.rows {
+				t.Errorf("%d constraints left, want %d", len(p.Constraints), tt.rows)
+			}
+		})
+	}
+}
+
+func TestBoundSources(t *testing.T) {
+	p, err := ParseProblem("x1 + x2", []string{"x1 <= 3", "x1 + x2 <= 4", "x3 free", "x2 >= 1", "x4 <= 0"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"x1 <= 3", "x2 >= 1", "x4 <= 0"}
+	if len(p.BoundSources) != len(want) {
+		t.Fatalf("BoundSources = %v, want %v", p.BoundSources, want)
+	}
+	for i, eq := range p.BoundSources {
+		if eq.String() != want[i] {
+			t.Errorf("BoundSources[%d] = %v, want %s", i, eq, want[i])
+		}
+	}
+}
+
+func TestStandard(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints []string
+		v           string
+		sub         string // Substitution of v, "" if it keeps its own value
+		vars        []string
+		rows        int
+	}{
+		{"default", []string{"x1 <= 4"}, "x1", "", []string{"x1"}, 0},
+		{"shifted", []string{"x1 >= 2", "x1 <= 5"}, "x1", "2 + x1", []string{"x1"}, 0},
+		{"upper only", []string{"x1 <= 5", "x1 free"}, "x1", "5 - x1-", []string{"x1-"}, 0},
+		{"non-positive", []string{"x1 <= 0"}, "x1", "0 - x1-", []string{"x1-"}, 0},
+		{"free", []string{"x1 free"}, "x1", "0 + x1+ - x1-", []string{"x1+", "x1-"}, 0},
+		{"empty", []string{"x1 >= 3", "x1 <= 1"}, "x1", "3 + x1", []string{"x1"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseProblem("x1", tt.constraints, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, subs := p.Standard()
+			sub, ok := subs[tt.v]
+			if got := substitution(sub); ok != (tt.sub != "") || ok && got != tt.sub {
+				t.Errorf("%s = %s, want %q", tt.v, got, tt.sub)
+			}
+			if len(s.Variables) != len(tt.vars) {
+				t.Errorf("variables %v, want %v", s.Variables, tt.vars)
+			}
+			for _, v := range tt.vars {
+				if !s.Variables[v] {
+					t.Errorf("%s is missing from %v", v, s.Variables)
+				}
+				if l, ok := s.Bound(v).Lower.Value(); !ok || !l.IsZero() {
+					t.Errorf("%s is not non-negative: %v", v, s.Bound(v))
+				}
+			}
+			if len(s.Constraints) != tt.rows {
+				t.Errorf("constraints %v, want %d", s.Constraints, tt.rows)
+			}
+		})
+	}
+}
+
+// substitution writes sub as "constant + x - y"
+func substitution(sub Substitution) string {
+	s := sub.Constant.String()
+	for _, term := range sub.Terms {
+		switch {
+		case term.Coefficient.Cmp(fr.New(1, 1)) == 0:
+			s += " + " + term.Variable
+		case term.Coefficient.Cmp(fr.New(-1, 1)) == 0:
+			s += " - " + term.Variable
+		default:
+			s += " + " + term.Coefficient.String() + term.Variable
+		}
+	}
+	return s
+}
+
+// TestStandardKeepsUpperBound checks that a shifted variable keeps the width
+// of its range as the upper bound of the copy
+func TestStandardKeepsUpperBound(t *testing.T) {
+	p, err := ParseProblem("x1", []string{"x1 >= 2", "x1 <= 5"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ := p.Standard()
+	if u, ok := s.Bound("x1").Upper.Value(); !ok || u.Cmp(fr.New(3, 1)) != 0 {
+		t.Errorf("upper bound of x1 = %v, want 3", s.Bound("x1").Upper)
+	}
+}
+
+func TestSlack(t *testing.T) {
+	values := map[string]fr.Fraction{"x1": fr.New(1, 2), "x2": fr.New(2, 1)}
+	tests := []struct {
+		eq   string
+		want fr.Fraction
+	}{
+		{"x1 + x2 <= 4", fr.New(3, 2)},
+		{"2x1 + x2 >= 1", fr.New(2, 1)},
+		{"x1 + 5 <= 6", fr.New(1, 2)},
+		{"x1 + x2 = 5/2", fr.New(0, 1)},
+	}
+	for _, tt := range tests {
+		p, err := ParseProblem("x1", []string{tt.eq, "x1 + x2 + x3 <= 9"}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		eq := p.Constraints[0]
+		if len(p.BoundSources) > 0 {
+			eq = p.BoundSources[0]
+		}
+		if got := Slack(eq, values); got.Cmp(tt.want) != 0 {
+			t.Errorf("slack of %s = %v, want %v", tt.eq, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, c := range []string{"x1 + x2", "x1 + x2 <= y"} {
+		if _, err := ParseProblem("x1", []string{c}, true); err == nil {
+			t.Errorf("%q parses", c)
+		}
+	}
+}
+
+func TestFreshName(t *testing.T) {
+	p := &Problem{Variables: map[string]bool{"s1": true, "s1'": true, "a2": true}}
+	for _, tt := range []struct {
+		prefix string
+		i      int
+		want   string
+	}{{"s", 1, "s1''"}, {"s", 2, "s2"}, {"a", 2, "a2'"}} {
+		if got := FreshName(p, tt.prefix, tt.i); got != tt.want {
+			t.Errorf("FreshName(%s, %d) = %s, want %s", tt.prefix, tt.i, got, tt.want)
+		}
+	}
+}
+
+func TestConvertToTableau(t *testing.T) {
+	p, err := ParseProblem("3x1 + 2x2", []string{"x1 + x2 <= 4", "x1 + 3x2 >= 6"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tab := ConvertToTableau[fr.Fraction](p)
+	if !tab.InPhaseOne() {
+		t.Error("a \">=\" row does not start Phase I")
+	}
+	wantRows := []string{"s1", "a2", "F", "W"}
+	wantCols := []string{"x1", "x2", "e2", "const"}
+	if len(tab.RowNames) != len(wantRows) || len(tab.ColNames) != len(wantCols) {
+		t.Fatalf("rows %v, columns %v, want %v and %v", tab.RowNames, tab.ColNames, wantRows, wantCols)
+	}
+	for i, v := range wantRows {
+		if tab.RowNames[i] != v {
+			t.Errorf("rows %v, want %v", tab.RowNames, wantRows)
+			break
+		}
+	}
+	for j, v := range wantCols {
+		if tab.ColNames[j] != v {
+			t.Errorf("columns %v, want %v", tab.ColNames, wantCols)
+			break
+		}
+	}
+	if got := tab.Table[1][3]; got.Cmp(fr.New(6, 1)) != 0 {
+		t.Errorf("const of a2 = %v, want 6", got)
+	}
+}
@@ -0,0 +1,60 @@
+package revised
+
+import (
+	"simplex/field"
+	"simplex/linalg"
+)
+
+// eta is a product-form update: the basis column in position r was replaced
+// by a column whose representation in the old basis is w
+type eta[T field.Element[T]] struct {
+	r int
+	w []T
+}
+
+// inverse represents B⁻¹ as E_k ⋯ E_1 · (L·U)⁻¹, the LU factorisation of the
+// last refactorised basis followed by one eta per pivot since
+type inverse[T field.Element[T]] struct {
+	lu   *linalg.LU[T]
+	etas []eta[T]
+}
+
+// ftran returns B⁻¹ a
+func (inv *inverse[T]) ftran(a []T) []T {
+	v := inv.lu.Solve(a)
+	for _, e := range inv.etas {
+		if v[e.r].IsZero() {
+			continue
+		}
+		t := v[e.r].Div(e.w[e.r])
+		for i := range v {
+			if i != e.r && !e.w[i].IsZero() {
+				v[i] = v[i].Sub(e.w[i].Mul(t))
+			}
+		}
+		v[e.r] = t
+	}
+	return v
+}
+
+// btran returns y with yᵀ = cᵀ B⁻¹
+func (inv *inverse[T]) btran(c []T) []T {
+	v := append([]T(nil), c...)
+	for k := len(inv.etas) - 1; k >= 0; k-- {
+		e := inv.etas[k]
+		t := v[e.r]
+		for i := range v {
+			if i != e.r && !e.w[i].IsZero() && !v[i].IsZero() {
+				t = t.Sub(v[i].Mul(e.w[i]))
+			}
+		}
+		v[e.r] = t.Div(e.w[e.r])
+	}
+	return inv.lu.SolveTranspose(v)
+}
+
+// update records that the basis column in position r was replaced by the
+// column with ftran representation w
+func (inv *inverse[T]) update(r int, w []T) {
+	inv.etas = append(inv.etas, eta[T]{r: r, w: w})
+}
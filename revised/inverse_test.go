@@ -0,0 +1,70 @@
+package revised
+
+import (
+	"fmt"
+	"testing"
+
+	fr "simplex/fraction"
+	"simplex/linalg"
+)
+
+// TestProductForm replaces basis columns one at a time and checks that ftran
+// and btran through the etas agree with solving against the new basis, before
+// and after refactorising it
+func TestProductForm(t *testing.T) {
+	b := linalg.Identity[fr.Fraction](3)
+	lu, _ := linalg.Factorize(b)
+	inv := inverse[fr.Fraction]{lu: lu}
+
+	columns := []struct {
+		r int
+		a []int
+	}{
+		{0, []int{2, 1, 1}},
+		{2, []int{1, 0, 4}},
+		{1, []int{3, 5, -1}},
+		{0, []int{0, 1, 1}},
+	}
+	rhs := linalg.Vector[fr.Fraction]{fr.New(1, 1), fr.New(-2, 1), fr.New(3, 2)}
+
+	check := func(step string, inv *inverse[fr.Fraction]) {
+		x := inv.ftran(rhs)
+		if bx, _ := b.MulVec(x); !vecEqual(bx, rhs) {
+			t.Errorf("%s: ftran gives x = %v with B x = %v, want %v", step, x, bx, rhs)
+		}
+		y := inv.btran(rhs)
+		if yb, _ := b.Transpose().MulVec(y); !vecEqual(yb, rhs) {
+			t.Errorf("%s: btran gives y = %v with yᵀ B = %v, want %v", step, y, yb, rhs)
+		}
+	}
+
+	for k, c := range columns {
+		a := make([]fr.Fraction, 3)
+		for i, v := range c.a {
+			a[i] = fr.New(v, 1)
+		}
+		inv.update(c.r, inv.ftran(a))
+		for i := range b {
+			b[i][c.r] = a[i]
+		}
+		check(fmt.Sprintf("after update %d", k+1), &inv)
+	}
+	if len(inv.etas) != len(columns) {
+		t.Fatalf("%d etas, want %d", len(inv.etas), len(columns))
+	}
+
+	lu, err := linalg.Factorize(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("after refactorising", &inverse[fr.Fraction]{lu: lu})
+}
+
+func vecEqual(a, b linalg.Vector[fr.Fraction]) bool {
+	for i := range a {
+		if fr.Cmp(a[i], b[i]) != 0 {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
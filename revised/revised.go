@@ -0,0 +1,395 @@
+// Package revised implements the revised simplex method. It works from the
+// constraint matrix of a parser.Problem and keeps only an LU factorisation of
+// the basis, updated in product form, instead of a full tableau.
+package revised
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+
+	"simplex/field"
+	"simplex/linalg"
+	"simplex/parser"
+	tb "simplex/tableau"
+)
+
+// Options tune Solve. Zero values select the defaults.
+type Options struct {
+	MaxIter  int // Iteration limit over both phases, 1000 by default
+	Refactor int // Pivots between refactorisations of the basis, 50 by default
+}
+
+// Result is the outcome of Solve
+type Result[T field.Element[T]] struct {
+	Status     tb.Status
+	Objective  T            // Value of the objective function, for min or max
+	Values     map[string]T // Value of each decision variable
+	Basis      []string     // Basic variable of each constraint
+	Iterations int
+}
+
+// column is a sparse column of the constraint matrix
+type column[T field.Element[T]] struct {
+	idx []int
+	val []T
+}
+
+type solver[T field.Element[T]] struct {
+	m          int
+	cols       []column[T]
+	names      []string
+	artificial []bool
+	b          []T
+
+	basis []int // Column basic in each row
+	pos   []int // Row of each basic column, -1 for nonbasic ones
+	x     []T   // Value of each basic variable
+	inv   inverse[T]
+
+	bland bool            // Use Bland's rule after a basis repeated
+	seen  map[uint64]bool // Bases visited, to detect cycling
+
+	subs map[string]parser.Substitution // Of the variables Standard replaced
+	opts Options
+	iter int
+}
+
+// Solve solves p with the revised simplex method over T. Constraints get
+// slack, surplus and artificial variables as in parser.ConvertToTableau, and
+// the artificial variables are removed by a Phase I. Variables are made
+// non-negative as in parser.Problem.Standard, and their bounds become rows.
+// Like simplex.Solve it returns the result with an error matching
+// tableau.ErrInfeasible, tableau.ErrUnbounded or tableau.ErrIterationLimit
+// when the status is not Optimal, and no result for a failure of the
+// arithmetic.
+func Solve[T field.Element[T]](original *parser.Problem, opts Options) (*Result[T], error) {
+	if opts.MaxIter <= 0 {
+		opts.MaxIter = 1000
+	}
+	if opts.Refactor <= 0 {
+		opts.Refactor = 50
+	}
+	p, subs := original.Standard()
+
+	vars := make([]string, 0, len(p.Variables))
+	for v := range p.Variables {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+
+	constraints := append(slices.Clone(p.Constraints), p.BoundConstraints()...)
+	s := &solver[T]{m: len(constraints), subs: subs, opts: opts, seen: make(map[uint64]bool)}
+	s.build(original, constraints, vars)
+
+	res := &Result[T]{Values: make(map[string]T)}
+	status, err := s.phaseOne()
+	if err == nil && status == tb.Optimal {
+		obj := make([]T, len(vars))
+		var constant T
+		for _, term := range p.ObjectiveFunction.LHS {
+			c := field.From[T](term.Coefficient)
+			if term.Variable == "" {
+				constant = constant.Add(c)
+			} else {
+				j := sort.SearchStrings(vars, term.Variable)
+				obj[j] = obj[j].Add(c)
+			}
+		}
+
+		// run minimises, so a maximisation uses -c
+		cost := make([]T, len(s.cols))
+		for j, c := range obj {
+			if p.IsMaximization {
+				c = c.Neg()
+			}
+			cost[j] = c
+		}
+		status, err = s.run("Phase II", cost)
+
+		res.Objective = constant
+		for i, j := range s.basis {
+			if j < len(vars) {
+				res.Values[vars[j]] = s.x[i]
+				res.Objective = res.Objective.Add(obj[j].Mul(s.x[i]))
+			}
+		}
+	}
+	if status == tb.Failed {
+		return nil, err
+	}
+
+	for _, v := range vars {
+		if _, ok := res.Values[v]; !ok {
+			var zero T
+			res.Values[v] = zero
+		}
+	}
+	values := make(map[string]T, len(subs))
+	for v, sub := range subs {
+		value := field.From[T](sub.Constant)
+		for _, term := range sub.Terms {
+			value = value.Add(field.From[T](term.Coefficient).Mul(res.Values[term.Variable]))
+			delete(res.Values, term.Variable)
+		}
+		values[v] = value
+	}
+	for v, value := range values {
+		res.Values[v] = value
+	}
+	for _, j := range s.basis {
+		res.Basis = append(res.Basis, s.names[j])
+	}
+	res.Status = status
+	res.Iterations = s.iter
+	return res, err
+}
+
+// build sets up the columns of the decision variables, then a slack for each
+// "<=" row, a surplus and an artificial variable for each ">=" row and an
+// artificial variable for each "=" row, with the slack and artificial
+// variables as the starting basis
+func (s *solver[T]) build(p *parser.Problem, constraints []parser.Equation, vars []string) {
+	s.cols = make([]column[T], len(vars))
+	s.names = slices.Clone(vars)
+	s.artificial = make([]bool, len(vars))
+	s.b = make([]T, s.m)
+	s.basis = make([]int, s.m)
+
+	add := func(name string, i int, v T, artificial bool) int {
+		s.cols = append(s.cols, column[T]{idx: []int{i}, val: []T{v}})
+		s.names = append(s.names, name)
+		s.artificial = append(s.artificial, artificial)
+		return len(s.cols) - 1
+	}
+	one := field.One[T]()
+
+	for i, constraint := range constraints {
+		eq := constraint.Normalized()
+		s.b[i] = field.From[T](eq.RHS)
+		for _, term := range eq.LHS {
+			if term.Coefficient.IsZero() {
+				continue
+			}
+			j := sort.SearchStrings(vars, term.Variable)
+			s.cols[j].idx = append(s.cols[j].idx, i)
+			s.cols[j].val = append(s.cols[j].val, field.From[T](term.Coefficient))
+		}
+
+		switch eq.Relation {
+		case "<=":
+			s.basis[i] = add(parser.FreshName(p, "s", i+1), i, one, false)
+		case ">=":
+			add(parser.FreshName(p, "e", i+1), i, one.Neg(), false)
+			s.basis[i] = add(parser.FreshName(p, "a", i+1), i, one, true)
+		default:
+			s.basis[i] = add(parser.FreshName(p, "a", i+1), i, one, true)
+		}
+	}
+
+	// The starting basis is the identity
+	s.pos = make([]int, len(s.cols))
+	for j := range s.pos {
+		s.pos[j] = -1
+	}
+	for i, j := range s.basis {
+		s.pos[j] = i
+	}
+	s.x = slices.Clone(s.b)
+	lu, _ := linalg.Factorize(linalg.Identity[T](s.m))
+	s.inv = inverse[T]{lu: lu}
+}
+
+// phaseOne minimises the sum of the artificial variables and then pivots
+// those left in the basis at zero out of it where the constraint allows
+func (s *solver[T]) phaseOne() (tb.Status, error) {
+	cost := make([]T, len(s.cols))
+	hasArtificial := false
+	for j, a := range s.artificial {
+		if a {
+			cost[j] = field.One[T]()
+			hasArtificial = true
+		}
+	}
+	if !hasArtificial {
+		return tb.Optimal, nil
+	}
+
+	status, err := s.run("Phase I", cost)
+	if err != nil || status != tb.Optimal {
+		return status, err
+	}
+	for i, j := range s.basis {
+		if s.artificial[j] && s.x[i].Sign() > 0 {
+			return tb.Infeasible, &tb.InfeasibleError{Row: s.names[j], Value: s.x[i].String()}
+		}
+	}
+
+	for i, j := range s.basis {
+		if !s.artificial[j] {
+			continue
+		}
+		e := make([]T, s.m)
+		e[i] = field.One[T]()
+		row := s.inv.btran(e)
+		for q := range s.cols {
+			if s.artificial[q] || s.pos[q] >= 0 {
+				continue
+			}
+			if !s.dot(row, q).IsZero() {
+				if err := s.pivot(i, q, s.inv.ftran(s.dense(q))); err != nil {
+					return tb.Failed, err
+				}
+				break
+			}
+		}
+	}
+	return tb.Optimal, nil
+}
+
+// run minimises cost·x from the current basis. Artificial variables never
+// enter, so in Phase I cost must make them leave. phase names the phase in
+// errors.
+func (s *solver[T]) run(phase string, cost []T) (tb.Status, error) {
+	for {
+		if s.iter >= s.opts.MaxIter {
+			return tb.IterationLimit, &tb.IterationLimitError{Phase: phase, Limit: s.opts.MaxIter}
+		}
+
+		cb := make([]T, s.m)
+		for i, j := range s.basis {
+			cb[i] = cost[j]
+		}
+		y := s.inv.btran(cb)
+
+		// Price the nonbasic columns: Dantzig's most negative reduced cost,
+		// or the first negative one under Bland's rule
+		q := -1
+		var best T
+		for j := range s.cols {
+			if s.artificial[j] || s.pos[j] >= 0 {
+				continue
+			}
+			d := cost[j].Sub(s.dot(y, j))
+			if d.Sign() < 0 && (q == -1 || !s.bland && d.Cmp(best) < 0) {
+				q, best = j, d
+				if s.bland {
+					break
+				}
+			}
+		}
+		if q == -1 {
+			return tb.Optimal, nil
+		}
+
+		w := s.inv.ftran(s.dense(q))
+		r := -1
+		var ratio T
+		for i := range w {
+			if w[i].Sign() <= 0 {
+				continue
+			}
+			t := s.x[i].Div(w[i])
+			if c := t.Cmp(ratio); r == -1 || c < 0 || c == 0 && s.basis[i] < s.basis[r] {
+				r, ratio = i, t
+			}
+		}
+		if r == -1 {
+			return tb.Unbounded, s.unbounded(q)
+		}
+
+		if err := s.pivot(r, q, w); err != nil {
+			return tb.Failed, err
+		}
+		s.iter++
+
+		key := s.key()
+		if s.seen[key] {
+			s.bland = true
+		}
+		s.seen[key] = true
+	}
+}
+
+// unbounded returns the error for column q improving the objective without
+// limit, naming the variable of the problem it belongs to
+func (s *solver[T]) unbounded(q int) error {
+	for v, sub := range s.subs {
+		for _, term := range sub.Terms {
+			if term.Variable == s.names[q] {
+				return &tb.UnboundedError{Column: v, Decreasing: term.Coefficient.Sign() < 0}
+			}
+		}
+	}
+	return &tb.UnboundedError{Column: s.names[q]}
+}
+
+// pivot brings column q into the basis in position r, where w = B⁻¹ a_q
+func (s *solver[T]) pivot(r, q int, w []T) error {
+	t := s.x[r].Div(w[r])
+	for i := range s.x {
+		if i != r && !w[i].IsZero() {
+			s.x[i] = s.x[i].Sub(w[i].Mul(t))
+		}
+	}
+	s.x[r] = t
+	s.pos[s.basis[r]] = -1
+	s.pos[q] = r
+	s.basis[r] = q
+	s.inv.update(r, w)
+
+	if len(s.inv.etas) >= s.opts.Refactor {
+		return s.refactor()
+	}
+	return nil
+}
+
+// refactor factorises the current basis afresh and recomputes its values,
+// which drops the etas and the error they gathered
+func (s *solver[T]) refactor() error {
+	b := linalg.New[T](s.m, s.m)
+	for k, j := range s.basis {
+		for n, i := range s.cols[j].idx {
+			b[i][k] = s.cols[j].val[n]
+		}
+	}
+	lu, err := linalg.Factorize(b)
+	if err != nil {
+		return fmt.Errorf("refactorising after %d iterations: %w", s.iter, err)
+	}
+	s.inv = inverse[T]{lu: lu}
+	s.x = s.inv.ftran(s.b)
+	return nil
+}
+
+// dot returns y · a_j
+func (s *solver[T]) dot(y []T, j int) T {
+	var sum T
+	for n, i := range s.cols[j].idx {
+		if !y[i].IsZero() {
+			sum = sum.Add(y[i].Mul(s.cols[j].val[n]))
+		}
+	}
+	return sum
+}
+
+// dense returns column j as a dense vector
+func (s *solver[T]) dense(j int) []T {
+	a := make([]T, s.m)
+	for n, i := range s.cols[j].idx {
+		a[i] = s.cols[j].val[n]
+	}
+	return a
+}
+
+// key returns a hash of the set of basic columns
+func (s *solver[T]) key() uint64 {
+	cols := slices.Clone(s.basis)
+	slices.Sort(cols)
+	h := fnv.New64a()
+	for _, j := range cols {
+		fmt.Fprintf(h, "%d,", j)
+	}
+	return h.Sum64()
+}
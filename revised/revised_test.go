@@ -0,0 +1,99 @@
+package revised
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"simplex/field"
+	fr "simplex/fraction"
+	"simplex/parser"
+	"simplex/simplex"
+	tb "simplex/tableau"
+)
+
+var problems = []struct {
+	name        string
+	max         bool
+	objective   string
+	constraints []string
+	err         error // Expected outcome, nil for optimal
+}{
+	{"two constraints", true, "2x1 + x2", []string{"3x1 + x2 <= 4", "x1 + 3x2 <= 5"}, nil},
+	{"equality", false, "3x1 + 2x2 + 4x3", []string{"x1 + x2 + x3 >= 4", "x1 + 2x2 <= 6", "x3 <= 3", "x1 - x3 = 1"}, nil},
+	{"redundant equality", true, "x1 + x2", []string{"x1 + x2 = 2", "2x1 + 2x2 = 4", "x1 <= 1"}, nil},
+	// Beale's example, which cycles under Dantzig's rule without a guard
+	{"degenerate", true, "3/4x4 - 20x5 + 1/2x6 - 6x7", []string{"1/4x4 - 8x5 - x6 + 9x7 <= 0", "1/2x4 - 12x5 - 1/2x6 + 3x7 <= 0", "x6 + x8 <= 1"}, nil},
+	{"degenerate vertex", true, "x1 + x2", []string{"x1 + x2 <= 1", "x1 - x2 <= 1", "-x1 + x2 <= 1", "x1 <= 1"}, nil},
+	{"bounds", true, "2x1 + x2 - x3", []string{"x1 + x2 <= 4", "x1 <= 3", "x2 >= 1/2", "x3 free", "x3 + x1 >= -2"}, nil},
+	{"infeasible", true, "x1 + x2", []string{"x1 + x2 >= 5", "x1 + x2 <= 3"}, tb.ErrInfeasible},
+	{"unbounded", true, "x1 + x2", []string{"x1 - x2 <= 3"}, tb.ErrUnbounded},
+}
+
+// TestAgainstTableau checks that revised.Solve and the tableau solver give
+// the same outcome and objective
+func TestAgainstTableau(t *testing.T) {
+	for _, tt := range problems {
+		p, err := parser.ParseProblem(tt.objective, tt.constraints, tt.max)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		want, err := simplex.Solve(context.Background(), p, simplex.Options{})
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("%s: tableau gives %v, want %v", tt.name, err, tt.err)
+		}
+
+		// Refactor 1 refactorises the basis after every pivot
+		for _, opts := range []Options{{}, {Refactor: 1}} {
+			t.Run(fmt.Sprintf("%s/refactor=%d", tt.name, opts.Refactor), func(t *testing.T) {
+				exact, err := Solve[fr.Fraction](p, opts)
+				check(t, p, exact, err, want, tt.err)
+				float, err := Solve[field.Float](p, opts)
+				check(t, p, float, err, want, tt.err)
+			})
+		}
+	}
+}
+
+func check[T field.Element[T]](t *testing.T, p *parser.Problem, got *Result[T], err error, want *simplex.Solution, wantErr error) {
+	t.Helper()
+	var zero T
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("%T: err = %v, want %v", zero, err, wantErr)
+	}
+	if wantErr != nil {
+		return
+	}
+
+	obj := field.From[T](want.Objective.(fr.Fraction))
+	if got.Objective.Cmp(obj) != 0 {
+		t.Errorf("%T: objective = %v, tableau gives %v", zero, got.Objective, want.Objective)
+	}
+
+	// The optimum may differ, but must be feasible
+	for _, eq := range p.Constraints {
+		lhs := field.From[T](fr.New(0, 1))
+		for _, term := range eq.LHS {
+			c := field.From[T](term.Coefficient)
+			if term.Variable != "" {
+				c = c.Mul(got.Values[term.Variable])
+			}
+			lhs = lhs.Add(c)
+		}
+		c := lhs.Cmp(field.From[T](eq.RHS))
+		if eq.Relation == "<=" && c > 0 || eq.Relation == ">=" && c < 0 || eq.Relation == "=" && c != 0 {
+			t.Errorf("%T: %v is violated by %v", zero, eq, got.Values)
+		}
+	}
+	for v := range p.Variables {
+		b := p.Bound(v)
+		x := got.Values[v]
+		if l, ok := b.Lower.Value(); ok && x.Cmp(field.From[T](l)) < 0 {
+			t.Errorf("%T: %s = %v is below %v", zero, v, x, l)
+		}
+		if u, ok := b.Upper.Value(); ok && x.Cmp(field.From[T](u)) > 0 {
+			t.Errorf("%T: %s = %v is above %v", zero, v, x, u)
+		}
+	}
+}
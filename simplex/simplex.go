@@ -0,0 +1,230 @@
+// Package simplex solves the linear programs of package parser with the
+// tableau simplex method. It is the library form of the command: Solve runs
+// every phase and reports progress through Options.Trace instead of printing.
+package simplex
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"simplex/field"
+	fr "simplex/fraction"
+	"simplex/parser"
+	tb "simplex/tableau"
+)
+
+// Arithmetic selects the number type Solve computes with
+type Arithmetic int
+
+const (
+	Fraction Arithmetic = iota // fraction.Fraction, exact
+	Rat                        // field.Rat, exact with math/big
+	Float                      // field.Float, float64 with a tolerance
+)
+
+// ArithmeticNames lists the names ParseArithmetic accepts
+var ArithmeticNames = []string{"fraction", "rat", "float"}
+
+func (a Arithmetic) String() string {
+	if a >= 0 && int(a) < len(ArithmeticNames) {
+		return ArithmeticNames[a]
+	}
+	return fmt.Sprintf("Arithmetic(%d)", int(a))
+}
+
+// ParseArithmetic returns the Arithmetic called name
+func ParseArithmetic(name string) (Arithmetic, error) {
+	for i, n := range ArithmeticNames {
+		if n == name {
+			return Arithmetic(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown arithmetic %q, want one of %s", name, strings.Join(ArithmeticNames, ", "))
+}
+
+// Options tune Solve. Zero values select the defaults.
+type Options struct {
+	Rule       string // Pivot rule, one of tableau.RuleNames, "dantzig" by default
+	Seed       int64  // Seed of the random pivot rule
+	BigM       bool   // Use the Big-M method instead of Phase I for ">=" and "=" rows
+	MaxIter    int    // Iteration limit over all phases, 1000 by default
+	Arithmetic Arithmetic
+	Tracer     tb.Tracer // Receives the events of the run, if set
+}
+
+// Number is a value of a Solution. Its dynamic type follows
+// Options.Arithmetic: fraction.Fraction, field.Rat or field.Float.
+type Number = tb.Value
+
+// Solution is the outcome of Solve; see tableau.Solution. Objective, Values,
+// Slacks and BoundSlacks are only set when Status is tableau.Optimal, and Run
+// leaves BoundSlacks to Solve, which has the problem.
+type Solution struct {
+	Status      tb.Status
+	Objective   Number
+	Values      map[string]Number // Value of each variable of the problem
+	Slacks      []Number          // Slack or surplus of each constraint of the problem
+	BoundSlacks []Number          // Slack or surplus of each of Problem.BoundSources
+	Basis       tb.Basis
+	Iterations  int
+}
+
+// Solve solves p with the tableau simplex method. When p has no optimum it
+// returns the Solution, with its Status and final Basis, together with an
+// error that matches tableau.ErrInfeasible, tableau.ErrUnbounded or
+// tableau.ErrIterationLimit. Other errors come without a Solution: bad
+// Options, a failure of the arithmetic matching tableau.ErrNumeric, or the
+// error of ctx when it is done. Options.Tracer sees the failures as the
+// status tableau.Failed.
+func Solve(ctx context.Context, p *parser.Problem, opts Options) (*Solution, error) {
+	switch opts.Arithmetic {
+	case Fraction:
+		return solve[fr.Fraction](ctx, p, opts)
+	case Rat:
+		return solve[field.Rat](ctx, p, opts)
+	case Float:
+		return solve[field.Float](ctx, p, opts)
+	}
+	return nil, fmt.Errorf("simplex: unknown arithmetic %v", opts.Arithmetic)
+}
+
+func solve[T field.Element[T]](ctx context.Context, p *parser.Problem, opts Options) (*Solution, error) {
+	t := parser.ConvertToTableau[T](p)
+	sol, err := Run(ctx, &t, opts)
+	if sol == nil || sol.Status != tb.Optimal {
+		return sol, err
+	}
+
+	// Constraints on a single variable have no row, so their slacks come from
+	// the values
+	values := make(map[string]T, len(sol.Values))
+	for v, x := range sol.Values {
+		values[v] = x.(T)
+	}
+	sol.BoundSlacks = make([]Number, len(p.BoundSources))
+	for i, eq := range p.BoundSources {
+		sol.BoundSlacks[i] = parser.Slack(eq, values)
+	}
+	return sol, err
+}
+
+// Run is Solve for a tableau that the caller built with
+// parser.ConvertToTableau, so that it can keep watching t, for example with
+// a tableau.History as t.Tracer. T fixes the arithmetic in place of
+// Options.Arithmetic, and Options.Tracer replaces t.Tracer when set.
+func Run[T field.Element[T]](ctx context.Context, t *tb.Tableau[T], opts Options) (*Solution, error) {
+	if opts.Rule == "" {
+		opts.Rule = "dantzig"
+	}
+	if opts.MaxIter <= 0 {
+		opts.MaxIter = 1000
+	}
+	rule, err := tb.NewRule[T](opts.Rule, opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Rule = rule
+	if opts.Tracer != nil {
+		t.Tracer = opts.Tracer
+	}
+	if opts.BigM {
+		t.UseBigM()
+	}
+	if t.Tracer != nil {
+		t.Tracer.OnInit(t.Snapshot())
+	}
+
+	status, iterations, err := run(ctx, t, opts.MaxIter)
+	if t.Tracer != nil {
+		t.Tracer.OnTerminate(status, err, t.Snapshot())
+	}
+	if status == tb.Failed {
+		return nil, err
+	}
+
+	s := t.Solution(status)
+	sol := &Solution{Status: s.Status, Basis: s.Basis, Iterations: iterations}
+	if s.Status == tb.Optimal {
+		sol.Objective = s.Objective
+		sol.Values = make(map[string]Number, len(s.Values))
+		for v, x := range s.Values {
+			sol.Values[v] = x
+		}
+		sol.Slacks = make([]Number, len(s.Slacks))
+		for i, x := range s.Slacks {
+			sol.Slacks[i] = x
+		}
+	}
+	return sol, err
+}
+
+// run takes t through its phases, Phase I or Big-M first if t starts in one,
+// then Phase II, with at most maxIter pivots and bound flips in all. It
+// returns Failed with the error of a failure or of ctx, and the error that
+// matches any other status that is not Optimal.
+func run[T field.Element[T]](ctx context.Context, t *tb.Tableau[T], maxIter int) (tb.Status, int, error) {
+	iteration := 0
+	for {
+		phase := "Phase II"
+		if t.InPhaseOne() {
+			phase = "Phase I"
+		} else if t.InBigM() {
+			phase = "Big-M"
+		}
+		if t.Tracer != nil {
+			t.Tracer.OnPhaseChange(phase, t.Snapshot())
+		}
+
+		guard := tb.NewCycleGuard(t)
+		for !t.PhaseDone() {
+			if err := ctx.Err(); err != nil {
+				return tb.Failed, iteration, err
+			}
+			if iteration >= maxIter {
+				return tb.IterationLimit, iteration, &tb.IterationLimitError{Phase: phase, Limit: maxIter}
+			}
+
+			r, s := t.Pivot()
+			if tb.IsFlip(r, s) {
+				iteration++
+				step := tb.Step{Phase: phase, Iteration: iteration, Row: r, Col: s, Entering: t.ColNames[s]}
+				if t.Tracer != nil {
+					t.Tracer.OnPivotChosen(step, t.Snapshot())
+				}
+				t.Flip(s)
+				if t.Tracer != nil {
+					t.Tracer.OnTransform(step, t.Snapshot())
+				}
+				continue
+			}
+			if !tb.IsPivotValid(r, s) {
+				if err := t.Unbounded(); err != nil {
+					return tb.Unbounded, iteration, err
+				}
+				break // No improving pivot is left in this phase
+			}
+
+			iteration++
+			step := tb.Step{Phase: phase, Iteration: iteration, Row: r, Col: s, Entering: t.ColNames[s], Leaving: t.RowNames[r]}
+			if t.Tracer != nil {
+				t.Tracer.OnPivotChosen(step, t.Snapshot())
+			}
+			if err := t.Apply(r, s); err != nil {
+				return tb.Failed, iteration, fmt.Errorf("simplex: iteration %d: %w", iteration, err)
+			}
+			guard.After(t, step.Entering, step.Leaving)
+			if t.Tracer != nil {
+				t.Tracer.OnTransform(step, t.Snapshot())
+			}
+		}
+
+		if phase == "Phase II" {
+			return tb.Optimal, iteration, nil
+		}
+		if err := t.EndPhase(); err != nil {
+			return tb.Infeasible, iteration, err
+		}
+	}
+}
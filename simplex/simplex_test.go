@@ -0,0 +1,180 @@
+package simplex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"simplex/field"
+	fr "simplex/fraction"
+	"simplex/parser"
+	tb "simplex/tableau"
+)
+
+var problems = []struct {
+	name        string
+	max         bool
+	objective   string
+	constraints []string
+	want        string            // Optimal objective
+	values      map[string]string // Values at the optimum, where it is unique
+	err         error             // Expected outcome instead of an optimum
+}{
+	{"two constraints", true, "2x1 + x2", []string{"3x1 + x2 <= 4", "x1 + 3x2 <= 5"}, "25/8", map[string]string{"x1": "7/8", "x2": "11/8"}, nil},
+	{"surplus rows", false, "2x1 + 3x2", []string{"x1 + x2 >= 4", "x1 + 3x2 >= 6"}, "9", map[string]string{"x1": "3", "x2": "1"}, nil},
+	{"equality", false, "3x1 + 2x2 + 4x3", []string{"x1 + x2 + x3 >= 4", "x1 + 2x2 <= 6", "x3 <= 3", "x1 - x3 = 1"}, "10", map[string]string{"x1": "4/3", "x2": "7/3", "x3": "1/3"}, nil},
+	{"upper bounds", true, "3x1 + 5x2", []string{"x1 <= 4", "2x2 <= 12", "3x1 + 2x2 <= 18"}, "36", map[string]string{"x1": "2", "x2": "6"}, nil},
+	{"free", false, "x1 + 2x2", []string{"x1 free", "x1 + x2 >= -2"}, "-2", map[string]string{"x1": "-2", "x2": "0"}, nil},
+	{"non-positive", true, "x1 + 2x4", []string{"x4 <= 0", "x1 + x4 = 1", "x1 >= 3"}, "-1", map[string]string{"x1": "3", "x4": "-2"}, nil},
+	{"degenerate", true, "3/4x4 - 20x5 + 1/2x6 - 6x7", []string{"1/4x4 - 8x5 - x6 + 9x7 <= 0", "1/2x4 - 12x5 - 1/2x6 + 3x7 <= 0", "x6 + x8 <= 1"}, "5/4", map[string]string{"x4": "1", "x6": "1"}, nil},
+	{"infeasible", true, "x1 + x2", []string{"x1 + x2 >= 5", "x1 + x2 <= 3"}, "", nil, tb.ErrInfeasible},
+	{"unbounded", true, "x1 + x2", []string{"x1 - x2 <= 3"}, "", nil, tb.ErrUnbounded},
+}
+
+// TestSolve solves each problem with every arithmetic, pivot rule and
+// initialisation
+func TestSolve(t *testing.T) {
+	for _, tt := range problems {
+		p, err := parser.ParseProblem(tt.objective, tt.constraints, tt.max)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for _, arith := range []Arithmetic{Fraction, Rat, Float} {
+			for _, rule := range tb.RuleNames {
+				for _, bigM := range []bool{false, true} {
+					opts := Options{Rule: rule, BigM: bigM, Arithmetic: arith}
+					t.Run(fmt.Sprintf("%s/%v/%s/bigm=%v", tt.name, arith, rule, bigM), func(t *testing.T) {
+						sol, err := Solve(context.Background(), p, opts)
+						if !errors.Is(err, tt.err) {
+							t.Fatalf("err = %v, want %v", err, tt.err)
+						}
+						if tt.err != nil {
+							if sol == nil || sol.Status == tb.Optimal || sol.Values != nil {
+								t.Errorf("solution %+v with %v", sol, err)
+							}
+							return
+						}
+						if sol.Status != tb.Optimal {
+							t.Fatalf("status %v", sol.Status)
+						}
+						if !equal(arith, sol.Objective, tt.want) {
+							t.Errorf("objective = %v, want %s", sol.Objective, tt.want)
+						}
+						for v, want := range tt.values {
+							if !equal(arith, sol.Values[v], want) {
+								t.Errorf("%s = %v, want %s", v, sol.Values[v], want)
+							}
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+// equal reports whether x, of the type that arith selects, is the fraction s
+func equal(arith Arithmetic, x Number, s string) bool {
+	f, err := fr.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	switch arith {
+	case Fraction:
+		y, ok := x.(fr.Fraction)
+		return ok && y.Cmp(f) == 0
+	case Rat:
+		y, ok := x.(field.Rat)
+		return ok && y.Cmp(field.From[field.Rat](f)) == 0
+	case Float:
+		y, ok := x.(field.Float)
+		return ok && y.Cmp(field.From[field.Float](f)) == 0
+	}
+	return false
+}
+
+func TestSlacks(t *testing.T) {
+	p, err := parser.ParseProblem("3x1 + 2x2 + 4x3", []string{"x1 + x2 + x3 >= 4", "x1 + 2x2 <= 6", "x3 <= 3", "x1 - x3 = 1", "x2 >= 1"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol, err := Solve(context.Background(), p, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slacks := []string{"0", "0", "0"}
+	for i, want := range slacks {
+		if !equal(Fraction, sol.Slacks[i], want) {
+			t.Errorf("slack of %v = %v, want %s", p.Constraints[i], sol.Slacks[i], want)
+		}
+	}
+	// x2 = 7/3 and x3 = 1/3 at the optimum
+	boundSlacks := []string{"8/3", "4/3"}
+	if len(sol.BoundSlacks) != len(boundSlacks) {
+		t.Fatalf("bound slacks %v, want %v", sol.BoundSlacks, boundSlacks)
+	}
+	for i, want := range boundSlacks {
+		if !equal(Fraction, sol.BoundSlacks[i], want) {
+			t.Errorf("slack of %v = %v, want %s", p.BoundSources[i], sol.BoundSlacks[i], want)
+		}
+	}
+}
+
+func TestIterationLimit(t *testing.T) {
+	p, err := parser.ParseProblem("3x1 + 2x2 + 4x3", []string{"x1 + x2 + x3 >= 4", "x1 + 2x2 <= 6", "x3 <= 3", "x1 - x3 = 1"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol, err := Solve(context.Background(), p, Options{MaxIter: 1})
+	var limit *tb.IterationLimitError
+	if !errors.As(err, &limit) || limit.Limit != 1 || limit.Phase != "Phase I" {
+		t.Fatalf("err = %v, want the limit of 1 in Phase I", err)
+	}
+	if sol.Status != tb.IterationLimit || sol.Iterations != 1 {
+		t.Errorf("status %v after %d iterations", sol.Status, sol.Iterations)
+	}
+}
+
+func TestBadOptions(t *testing.T) {
+	p, err := parser.ParseProblem("x1", []string{"x1 <= 1"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range []Options{{Rule: "fastest"}, {Arithmetic: Arithmetic(7)}} {
+		if sol, err := Solve(context.Background(), p, opts); sol != nil || err == nil {
+			t.Errorf("%+v: %+v, %v", opts, sol, err)
+		}
+	}
+}
+
+// TestCanceled checks that a cancelled context fails the run without a
+// Solution and that the tracer hears of it
+func TestCanceled(t *testing.T) {
+	p, err := parser.ParseProblem("x1 + x2", []string{"x1 + 2x2 <= 4"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec := &tb.Recorder{}
+	sol, err := Solve(ctx, p, Options{Tracer: rec})
+	if sol != nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("%+v, %v, want context.Canceled", sol, err)
+	}
+	last := rec.Events[len(rec.Events)-1]
+	if last.Kind != "terminate" || last.Status != tb.Failed || !errors.Is(last.Err, context.Canceled) {
+		t.Errorf("last event %s %v %v, want terminate failed", last.Kind, last.Status, last.Err)
+	}
+}
+
+func TestParseArithmetic(t *testing.T) {
+	for i, name := range ArithmeticNames {
+		a, err := ParseArithmetic(name)
+		if err != nil || a != Arithmetic(i) || a.String() != name {
+			t.Errorf("ParseArithmetic(%s) = %v, %v", name, a, err)
+		}
+	}
+	if _, err := ParseArithmetic("decimal"); err == nil {
+		t.Error("ParseArithmetic accepts decimal")
+	}
+}
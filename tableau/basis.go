@@ -0,0 +1,75 @@
+package tableau
+
+import (
+  "hash/fnv"
+  "slices"
+  "strings"
+)
+
+// Basis records which variable is basic in each constraint row of a tableau
+// and which variable is nonbasic in each column. Exchange keeps it current
+// by swapping the names of the pivot row and column, so any nonbasic column
+// may enter again later.
+type Basis struct {
+  Basic []string // Basic[i] is the variable of row i
+  Nonbasic []string // Nonbasic[j] is the variable of column j
+  AtUpper []string // Nonbasic variables at their upper bound, sorted
+}
+
+// Basis returns the current basis of t
+func (t *Tableau[T]) Basis() Basis {
+  b := Basis{
+    Basic:    slices.Clone(t.RowNames[:t.rows()]),
+    Nonbasic: slices.Clone(t.ColNames[:len(t.ColNames)-1]),
+  }
+  for _, v := range b.Nonbasic {
+    if t.flipped[v] {
+      b.AtUpper = append(b.AtUpper, v)
+    }
+  }
+  slices.Sort(b.AtUpper)
+  return b
+}
+
+// Row returns the row in which v is basic, or -1
+func (b Basis) Row(v string) int {
+  return slices.Index(b.Basic, v)
+}
+
+// Col returns the column of the nonbasic variable v, or -1
+func (b Basis) Col(v string) int {
+  return slices.Index(b.Nonbasic, v)
+}
+
+func (b Basis) IsBasic(v string) bool {
+  return b.Row(v) >= 0
+}
+
+// Key returns a hash of the set of basic variables and of the variables at
+// their upper bound, equal for two bases that differ only in the order of
+// their rows
+func (b Basis) Key() uint64 {
+  names := slices.Clone(b.Basic)
+  slices.Sort(names)
+
+  h := fnv.New64a()
+  for _, name := range names {
+    h.Write([]byte(name))
+    h.Write([]byte{0})
+  }
+  h.Write([]byte{1})
+  for _, name := range b.AtUpper {
+    h.Write([]byte(name))
+    h.Write([]byte{0})
+  }
+  return h.Sum64()
+}
+
+func (b Basis) String() string {
+  return "{" + strings.Join(b.Basic, ", ") + "}"
+}
+
+// BasisKey returns the Key of the current basis of t
+func (t *Tableau[T]) BasisKey() uint64 {
+  return t.Basis().Key()
+}
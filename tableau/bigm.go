@@ -0,0 +1,107 @@
+package tableau
+
+import (
+  "fmt"
+  "strings"
+
+  "simplex/field"
+  fr "simplex/fraction"
+)
+
+// BigM is a value a·M + b of the Big-M method, kept as the pair M = a, C = b.
+// M stays symbolic and is larger than any number, so values compare by their
+// M part first and then by C.
+type BigM[T field.Element[T]] struct {
+  M, C T
+}
+
+func (x BigM[T]) Sign() int {
+  if s := x.M.Sign(); s != 0 {
+    return s
+  }
+  return x.C.Sign()
+}
+
+func (x BigM[T]) Cmp(y BigM[T]) int {
+  if c := x.M.Cmp(y.M); c != 0 {
+    return c
+  }
+  return x.C.Cmp(y.C)
+}
+
+// String formats x like "2M - 3", "-M", "1/2M + 5" or "0"
+func (x BigM[T]) String() string {
+  if x.M.IsZero() {
+    return x.C.String()
+  }
+
+  var b strings.Builder
+  switch m := x.M.String(); m {
+  case "1":
+  case "-1":
+    b.WriteString("-")
+  default:
+    b.WriteString(m)
+  }
+  b.WriteString("M")
+
+  switch x.C.Sign() {
+  case 1:
+    b.WriteString(" + " + x.C.String())
+  case -1:
+    b.WriteString(" - " + x.C.Neg().String())
+  }
+  return b.String()
+}
+
+// Format pads the String form, honouring the '+', ' ' and '-' flags and the
+// width, so that BigM values line up with numbers in Print
+func (x BigM[T]) Format(f fmt.State, verb rune) {
+  fr.Pad(f, x.String())
+}
+
+// UseBigM switches a tableau that ConvertToTableau started in Phase I to the
+// Big-M method. The W row becomes the part in M of the objective, which is
+// F - M·(sum of the artificial variables) when maximising and
+// F + M·(sum of the artificial variables) when minimising.
+func (t *Tableau[T]) UseBigM() {
+  if !t.phaseOne {
+    return
+  }
+  m := len(t.Table)
+  if !t.IsMaximization {
+    for j := range t.Table[m-1] {
+      t.Table[m-1][j] = t.Table[m-1][j].Neg()
+    }
+  }
+  t.RowNames[m-1] = "M"
+  t.phaseOne = false
+  t.bigM = true
+}
+
+// InBigM reports whether the objective still has a part in M
+func (t *Tableau[T]) InBigM() bool {
+  return t.bigM
+}
+
+// artificialPositive returns the row of an artificial variable above zero,
+// or -1
+func (t *Tableau[T]) artificialPositive() int {
+  n := len(t.Table[0])
+  for i := 0; i < t.rows(); i++ {
+    if t.artificial[t.RowNames[i]] && t.Table[i][n-1].Sign() > 0 {
+      return i
+    }
+  }
+  return -1
+}
+
+// artificialBasic reports whether an artificial variable is still basic
+func (t *Tableau[T]) artificialBasic() bool {
+  for i := 0; i < t.rows(); i++ {
+    if t.artificial[t.RowNames[i]] {
+      return true
+    }
+  }
+  return false
+}
@@ -0,0 +1,41 @@
+package tableau_test
+
+import (
+  "fmt"
+  "testing"
+
+  fr "simplex/fraction"
+  tb "simplex/tableau"
+)
+
+func TestBigM(t *testing.T) {
+  solveLPs(t, func(tab *tb.Tableau[fr.Fraction]) {
+    inPhaseOne := tab.InPhaseOne()
+    tab.UseBigM()
+    if tab.InBigM() != inPhaseOne || tab.InPhaseOne() {
+      t.Errorf("UseBigM gives Big-M %v, Phase I %v", tab.InBigM(), tab.InPhaseOne())
+    }
+  })
+}
+
+func TestBigMFormat(t *testing.T) {
+  tests := []struct {
+    format string
+    x tb.BigM[fr.Fraction]
+    want string
+  }{
+    {"%v", tb.BigM[fr.Fraction]{M: fr.New(2, 1), C: fr.New(-1, 3)}, "2M - 1/3"},
+    {"%v", tb.BigM[fr.Fraction]{M: fr.New(-1, 1), C: fr.New(3, 1)}, "-M + 3"},
+    {"%v", tb.BigM[fr.Fraction]{C: fr.New(5, 2)}, "5/2"},
+    {"%+v", tb.BigM[fr.Fraction]{M: fr.New(1, 1)}, "+M"},
+    {"%+v", tb.BigM[fr.Fraction]{C: fr.New(-4, 1)}, "-4"},
+    {"%6v|", tb.BigM[fr.Fraction]{M: fr.New(1, 1)}, "     M|"},
+    {"%-6v|", tb.BigM[fr.Fraction]{M: fr.New(1, 1)}, "M     |"},
+    {"% 4v|", tb.BigM[fr.Fraction]{C: fr.New(7, 1)}, "   7|"},
+  }
+  for _, tt := range tests {
+    if got := fmt.Sprintf(tt.format, tt.x); got != tt.want {
+      t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+    }
+  }
+}
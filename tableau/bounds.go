@@ -0,0 +1,116 @@
+package tableau
+
+import (
+  "simplex/field"
+)
+
+// recovery gives an original variable as constant + Σ coefficient·variable
+// over variables of the tableau
+type recovery[T field.Element[T]] struct {
+  constant T
+  terms map[string]T
+}
+
+// SetUpper gives variable v the upper bound u, so that 0 <= v <= u without a
+// row for the bound. The bounded simplex keeps a nonbasic variable at either
+// bound by replacing it with its complement u - v when it reaches u; Print
+// shows complemented variables with a trailing "'".
+func (t *Tableau[T]) SetUpper(v string, u T) {
+  if t.upper == nil {
+    t.upper = make(map[string]T)
+  }
+  t.upper[v] = u
+}
+
+// Upper returns the upper bound of variable v, if it has one
+func (t *Tableau[T]) Upper(v string) (T, bool) {
+  u, ok := t.upper[v]
+  return u, ok
+}
+
+// Recover makes GetSolution report the original variable v as constant plus
+// the sum of coefficient times the value of each tableau variable in terms,
+// for variables that the tableau holds in substituted form. The variables of
+// terms are left out of the solution, unless one is v itself.
+func (t *Tableau[T]) Recover(v string, constant T, terms map[string]T) {
+  if t.recover == nil {
+    t.recover = make(map[string]recovery[T])
+  }
+  t.recover[v] = recovery[T]{constant: constant, terms: terms}
+}
+
+// origin returns the original variable that the tableau variable v stands
+// for in a substitution given to Recover, and whether it decreases as v
+// increases. Other variables are their own origin.
+func (t *Tableau[T]) origin(v string) (string, bool) {
+  for x, rec := range t.recover {
+    if a, ok := rec.terms[v]; ok {
+      return x, a.Sign() < 0
+    }
+  }
+  return v, false
+}
+
+// limit returns how far the variable of column s can increase before the
+// basic variable of row i falls to zero or, with a negative entry, rises to
+// its upper bound. It reports false when the row sets no limit.
+func (t *Tableau[T]) limit(i, s int) (field.Ext[T], bool) {
+  n := len(t.Table[0])
+  a := t.Table[i][s]
+  switch a.Sign() {
+  case 1:
+    return field.Finite(t.Table[i][n-1].Div(a)), true // const / coefficient
+  case -1:
+    if u, ok := t.upper[t.RowNames[i]]; ok {
+      return field.Finite(u.Sub(t.Table[i][n-1]).Div(a.Neg())), true
+    }
+  }
+  return field.Ext[T]{}, false
+}
+
+// Flip moves the nonbasic variable x of column s to its other bound by
+// replacing it with its complement u - x, which moves the column times u into
+// const and negates the column
+func (t *Tableau[T]) Flip(s int) {
+  n := len(t.Table[0])
+  u := t.upper[t.ColNames[s]]
+  for i := range t.Table {
+    a := t.Table[i][s]
+    if !a.IsZero() {
+      t.Table[i][n-1] = t.Table[i][n-1].Sub(a.Mul(u))
+      t.Table[i][s] = a.Neg()
+    }
+  }
+  t.toggle(t.ColNames[s])
+}
+
+// flipRow replaces the basic variable x of row i by its complement u - x,
+// negating the row and taking const to u - const
+func (t *Tableau[T]) flipRow(i int) {
+  n := len(t.Table[0])
+  u := t.upper[t.RowNames[i]]
+  for j := 0; j < n-1; j++ {
+    t.Table[i][j] = t.Table[i][j].Neg()
+  }
+  t.Table[i][n-1] = u.Sub(t.Table[i][n-1])
+  t.toggle(t.RowNames[i])
+}
+
+func (t *Tableau[T]) toggle(v string) {
+  if t.flipped == nil {
+    t.flipped = make(map[string]bool)
+  }
+  if t.flipped[v] {
+    delete(t.flipped, v)
+  } else {
+    t.flipped[v] = true
+  }
+}
+
+// display returns the name Print shows for variable v
+func (t *Tableau[T]) display(v string) string {
+  if t.flipped[v] {
+    return v + "'"
+  }
+  return v
+}
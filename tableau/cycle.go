@@ -0,0 +1,63 @@
+package tableau
+
+import (
+  "fmt"
+  "slices"
+  "strings"
+
+  "simplex/field"
+)
+
+// Cycle is a basis that a simplex loop reached twice: first after iteration
+// First, again after iteration Repeat, through the pivots in Pivots
+type Cycle struct {
+  First, Repeat int
+  Pivots []string
+}
+
+func (c Cycle) String() string {
+  return fmt.Sprintf("basis of iteration %d repeats at iteration %d after pivots %s",
+    c.First, c.Repeat, strings.Join(c.Pivots, ", "))
+}
+
+// CycleGuard watches the bases visited by a simplex loop. When one repeats,
+// the loop is cycling on a degenerate vertex; the guard reports the cycle
+// and switches the tableau to Bland's rule, which cannot cycle, so the loop
+// still ends with an optimal or unbounded result.
+type CycleGuard[T field.Element[T]] struct {
+  seen map[uint64]int // Iteration at which each basis was first reached
+  pivots []string // Pivot of each iteration, "x1 <-> s2"
+  Cycles []Cycle
+}
+
+// NewCycleGuard returns a guard that starts from the current basis of t
+func NewCycleGuard[T field.Element[T]](t *Tableau[T]) *CycleGuard[T] {
+  g := &CycleGuard[T]{seen: make(map[uint64]int)}
+  g.seen[t.BasisKey()] = 0
+  return g
+}
+
+// After records a pivot that exchanged entering and leaving in t. It returns
+// true when the new basis was seen before, having switched t.Rule to Bland.
+func (g *CycleGuard[T]) After(t *Tableau[T], entering, leaving string) bool {
+  g.pivots = append(g.pivots, entering + " <-> " + leaving)
+  iteration := len(g.pivots)
+
+  key := t.BasisKey()
+  first, ok := g.seen[key]
+  if !ok {
+    g.seen[key] = iteration
+    return false
+  }
+
+  c := Cycle{First: first, Repeat: iteration, Pivots: slices.Clone(g.pivots[first:])}
+  g.Cycles = append(g.Cycles, c)
+  t.emit(func(tr Tracer, s *Snapshot) { tr.OnCycle(c, s) })
+  if _, ok := t.Rule.(*Bland[T]); !ok {
+    t.Rule = &Bland[T]{}
+  }
+
+  // Forget the bases visited so far; Bland's rule starts afresh from here
+  g.seen = map[uint64]int{key: iteration}
+  return true
+}
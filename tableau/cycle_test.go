@@ -0,0 +1,77 @@
+package tableau_test
+
+import (
+  "testing"
+
+  fr "simplex/fraction"
+  "simplex/parser"
+  tb "simplex/tableau"
+)
+
+// guarded runs Phase II of max objective subject to constraints with
+// Dantzig's rule under a CycleGuard, for at most 50 pivots
+func guarded(t *testing.T, objective string, constraints []string) (*tb.Tableau[fr.Fraction], *tb.CycleGuard[fr.Fraction], *tb.Recorder) {
+  t.Helper()
+  p, err := parser.ParseProblem(objective, constraints, true)
+  if err != nil {
+    t.Fatal(err)
+  }
+  tab := parser.ConvertToTableau[fr.Fraction](p)
+  rec := &tb.Recorder{}
+  tab.Tracer = rec
+  guard := tb.NewCycleGuard(&tab)
+  for i := 0; !tab.PhaseDone(); i++ {
+    if i == 50 {
+      t.Fatal("no optimum after 50 pivots")
+    }
+    r, s := tab.Pivot()
+    if !tb.IsPivotValid(r, s) {
+      t.Fatalf("no pivot:\n%v", tab.Snapshot())
+    }
+    entering, leaving := tab.ColNames[s], tab.RowNames[r]
+    if err := tab.Apply(r, s); err != nil {
+      t.Fatal(err)
+    }
+    guard.After(&tab, entering, leaving)
+  }
+  return &tab, guard, rec
+}
+
+func TestCycleGuard(t *testing.T) {
+  // Beale's example cycles through six bases under Dantzig's rule
+  tab, guard, rec := guarded(t, "3/4x4 - 20x5 + 1/2x6 - 6x7",
+    []string{"1/4x4 - 8x5 - x6 + 9x7 <= 0", "1/2x4 - 12x5 - 1/2x6 + 3x7 <= 0", "x6 + x8 <= 1"})
+  if len(guard.Cycles) == 0 {
+    t.Fatal("no cycle found")
+  }
+  c := guard.Cycles[0]
+  if c.Repeat <= c.First || len(c.Pivots) != c.Repeat-c.First {
+    t.Errorf("cycle %v", c)
+  }
+  if _, ok := tab.Rule.(*tb.Bland[fr.Fraction]); !ok {
+    t.Errorf("rule %T after the cycle, want Bland", tab.Rule)
+  }
+  if got := tab.Solution(tb.Optimal).Objective.String(); got != "5/4" {
+    t.Errorf("objective = %s, want 5/4", got)
+  }
+
+  cycles := 0
+  for _, e := range rec.Events {
+    if e.Kind == "cycle" {
+      cycles++
+    }
+  }
+  if cycles != len(guard.Cycles) {
+    t.Errorf("%d cycle events, want %d", cycles, len(guard.Cycles))
+  }
+}
+
+func TestCycleGuardQuiet(t *testing.T) {
+  tab, guard, _ := guarded(t, "3x1 + 5x2", []string{"x1 + x2 <= 4", "x1 + 3x2 <= 6", "2x1 + x2 <= 7"})
+  if len(guard.Cycles) != 0 {
+    t.Errorf("cycles %v without degeneracy", guard.Cycles)
+  }
+  if tab.Rule != nil {
+    t.Errorf("rule %T, want the default", tab.Rule)
+  }
+}
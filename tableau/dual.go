@@ -0,0 +1,130 @@
+package tableau
+
+import (
+  "errors"
+  "fmt"
+  "slices"
+
+  "simplex/field"
+)
+
+// DualPivot chooses a pivot for the dual simplex method. The row with the
+// most negative const leaves and the column from DualRatioTest enters, which
+// keeps the objective row optimal. It returns -1, -1 when no const is
+// negative, and also when the leaving row has no negative entry, since then
+// the problem has no feasible solution.
+func (t *Tableau[T]) DualPivot() (int, int) {
+  n := len(t.Table[0]) // Number of columns
+
+  r := -1
+  var mostNegative T
+  for i := 0; i < t.rows(); i++ { // Skip objective function rows
+    if t.Table[i][n-1].Sign() < 0 {
+      if r == -1 || t.Table[i][n-1].Cmp(mostNegative) < 0 {
+        r = i
+        mostNegative = t.Table[i][n-1]
+      }
+    }
+  }
+
+  if r == -1 {
+    return -1, -1
+  }
+
+  s, ratio := t.DualRatioTest(r)
+  if ratio.IsInf() {
+    return -1, -1 // No column can make the row non-negative
+  }
+
+  return r, s
+}
+
+// DualRatioTest finds the column to enter in place of row r: the smallest
+// |F_j / a_rj| over negative entries a_rj of the row, F being the objective
+// row, which lies above W or M during Phase I or Big-M. It returns -1 and +∞
+// when the row has no negative entry.
+func (t *Tableau[T]) DualRatioTest(r int) (int, field.Ext[T]) {
+  n := len(t.Table[0])
+  obj := t.Table[t.rows()]
+
+  s := -1
+  minRatio := field.Inf[T](1)
+  for j := 0; j < n-1; j++ { // Skip constant column
+    if t.Table[r][j].Sign() < 0 {
+      ratio := obj[j].Div(t.Table[r][j]) // F_j / a_rj, which is <= 0 when maximising
+      if t.IsMaximization {
+        ratio = ratio.Neg()
+      }
+      if field.Finite(ratio).Less(minRatio) {
+        s = j
+        minRatio = field.Finite(ratio)
+      }
+    }
+  }
+  return s, minRatio
+}
+
+// DualSimplex runs the dual simplex method from the current basis until
+// every const is non-negative. t should be optimal but infeasible, as after
+// AddRow or a change of the right-hand side of a solved tableau; then it
+// returns Optimal with t optimal again. It returns Infeasible with an
+// *InfeasibleError for a row that shows that no feasible solution exists,
+// IterationLimit with an *IterationLimitError after maxIter pivots, and
+// Failed with the error of ExchangeChecked when the arithmetic fails. A
+// tableau still in Phase I or Big-M has no optimal basis to start from and
+// fails at once.
+func (t *Tableau[T]) DualSimplex(maxIter int) (Status, error) {
+  if t.phaseOne || t.bigM {
+    return Failed, errors.New("tableau: dual simplex needs a tableau past Phase I and Big-M")
+  }
+  t.emit(func(tr Tracer, s *Snapshot) { tr.OnPhaseChange("Dual", s) })
+  status, err := t.dualLoop(maxIter)
+  t.emit(func(tr Tracer, s *Snapshot) { tr.OnTerminate(status, err, s) })
+  return status, err
+}
+
+func (t *Tableau[T]) dualLoop(maxIter int) (Status, error) {
+  n := len(t.Table[0])
+  iteration := 1
+  for !t.IsFeasible() {
+    if iteration > maxIter {
+      return IterationLimit, &IterationLimitError{Phase: "Dual", Limit: maxIter}
+    }
+
+    r, s := t.DualPivot()
+    if !IsPivotValid(r, s) {
+      // The row with the most negative const has no negative entry
+      r = -1
+      for i := 0; i < t.rows(); i++ {
+        if r == -1 || t.Table[i][n-1].Cmp(t.Table[r][n-1]) < 0 {
+          r = i
+        }
+      }
+      return Infeasible, &InfeasibleError{Row: t.RowNames[r], Value: t.Table[r][n-1].String()}
+    }
+
+    step := Step{Phase: "Dual", Iteration: iteration, Row: r, Col: s, Entering: t.ColNames[s], Leaving: t.RowNames[r]}
+    t.emit(func(tr Tracer, snap *Snapshot) { tr.OnPivotChosen(step, snap) })
+    if err := t.ExchangeChecked(r, s); err != nil {
+      return Failed, err
+    }
+    t.emit(func(tr Tracer, snap *Snapshot) { tr.OnTransform(step, snap) })
+    iteration++
+  }
+
+  return Optimal, nil
+}
+
+// AddRow adds the constraint name = row[n-1] - Σ row[j]·(column j), written
+// over the current columns, as a basic row above the objective row. A cut
+// added to a solved tableau usually has a negative const, which DualSimplex
+// then repairs.
+func (t *Tableau[T]) AddRow(name string, row []T) error {
+  if len(row) != len(t.Table[0]) {
+    return fmt.Errorf("tableau: row has %d entries, want %d", len(row), len(t.Table[0]))
+  }
+  i := t.rows()
+  t.Table = slices.Insert(t.Table, i, slices.Clone(row))
+  t.RowNames = slices.Insert(t.RowNames, i, name)
+  return nil
+}
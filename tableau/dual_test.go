@@ -0,0 +1,108 @@
+package tableau_test
+
+import (
+  "errors"
+  "testing"
+
+  fr "simplex/fraction"
+  "simplex/parser"
+  tb "simplex/tableau"
+)
+
+// solved returns the optimal tableau of max 3x1 + 2x2 subject to
+// x1 + x2 <= 4 and x1 + 3x2 <= 6, which is x1 = 4, x2 = 0
+func solved(t *testing.T) tb.Tableau[fr.Fraction] {
+  t.Helper()
+  p, err := parser.ParseProblem("3x1 + 2x2", []string{"x1 + x2 <= 4", "x1 + 3x2 <= 6"}, true)
+  if err != nil {
+    t.Fatal(err)
+  }
+  tab := parser.ConvertToTableau[fr.Fraction](p)
+  if status, err := drive(&tab); status != tb.Optimal {
+    t.Fatalf("status %v: %v", status, err)
+  }
+  return tab
+}
+
+// cut returns the row of x1 <= u over the columns of t, x1 being basic
+func cut(t *testing.T, tab *tb.Tableau[fr.Fraction], u fr.Fraction) []fr.Fraction {
+  t.Helper()
+  i := tab.Basis().Row("x1")
+  if i < 0 {
+    t.Fatalf("x1 is not basic in %v", tab.Basis())
+  }
+  // u - x1 = (u - const) - Σ -a_j·(column j)
+  n := len(tab.Table[i])
+  row := make([]fr.Fraction, n)
+  for j := 0; j < n-1; j++ {
+    row[j] = fr.Neg(tab.Table[i][j])
+  }
+  row[n-1] = fr.Sub(u, tab.Table[i][n-1])
+  return row
+}
+
+func TestDualSimplex(t *testing.T) {
+  tests := []struct {
+    name string
+    u fr.Fraction // Of the cut x1 <= u
+    status tb.Status
+    err error
+    want string
+  }{
+    {"cut", fr.New(3, 1), tb.Optimal, nil, "11"},
+    {"tight cut", fr.New(0, 1), tb.Optimal, nil, "4"},
+    {"loose cut", fr.New(5, 1), tb.Optimal, nil, "12"},
+    {"infeasible cut", fr.New(-1, 1), tb.Infeasible, tb.ErrInfeasible, ""},
+  }
+  for _, tt := range tests {
+    t.Run(tt.name, func(t *testing.T) {
+      tab := solved(t)
+      if err := tab.AddRow("c1", cut(t, &tab, tt.u)); err != nil {
+        t.Fatal(err)
+      }
+      rec := &tb.Recorder{}
+      tab.Tracer = rec
+      status, err := tab.DualSimplex(100)
+      if status != tt.status || !errors.Is(err, tt.err) {
+        t.Fatalf("DualSimplex = %v, %v, want %v, %v", status, err, tt.status, tt.err)
+      }
+      if last := rec.Events[len(rec.Events)-1]; last.Kind != "terminate" || last.Status != status {
+        t.Errorf("last event %s %v, want terminate %v", last.Kind, last.Status, status)
+      }
+      if status != tb.Optimal {
+        return
+      }
+      if !tab.IsFeasible() || !tab.IsOptimal() {
+        t.Errorf("tableau is not optimal and feasible:\n%v", tab.Snapshot())
+      }
+      if got := tab.Solution(status).Objective.String(); got != tt.want {
+        t.Errorf("objective = %s, want %s", got, tt.want)
+      }
+    })
+  }
+}
+
+// TestDualSimplexPhaseOne checks that DualSimplex refuses a tableau whose
+// last row is W, which is not the objective to keep optimal
+func TestDualSimplexPhaseOne(t *testing.T) {
+  for _, bigM := range []bool{false, true} {
+    p, err := parser.ParseProblem("x1 + x2", []string{"x1 + x2 >= 2", "x1 <= 3"}, false)
+    if err != nil {
+      t.Fatal(err)
+    }
+    tab := parser.ConvertToTableau[fr.Fraction](p)
+    if bigM {
+      tab.UseBigM()
+    }
+    if status, err := tab.DualSimplex(100); status != tb.Failed || err == nil {
+      t.Errorf("Big-M %v: DualSimplex = %v, %v, want a failure", bigM, status, err)
+    }
+  }
+}
+
+func TestAddRowLength(t *testing.T) {
+  tab := solved(t)
+  if err := tab.AddRow("c1", []fr.Fraction{fr.New(1, 1)}); err == nil {
+    t.Error("AddRow takes a row of the wrong length")
+  }
+}
@@ -0,0 +1,85 @@
+package tableau
+
+import (
+  "errors"
+  "fmt"
+)
+
+// Outcomes of the simplex method other than an optimum. The errors returned
+// for them carry details and match these with errors.Is.
+var (
+  ErrInfeasible = errors.New("tableau: problem is infeasible")
+  ErrUnbounded = errors.New("tableau: objective is unbounded")
+  ErrIterationLimit = errors.New("tableau: iteration limit reached")
+  ErrNumeric = errors.New("tableau: numeric failure")
+)
+
+// InfeasibleError names the row that shows the problem has no feasible
+// solution: W or an artificial variable that cannot reach zero, or a row
+// with a negative const that no column can repair
+type InfeasibleError struct {
+  Row string // Basic variable of the row
+  Value string // Its value
+}
+
+func (e *InfeasibleError) Error() string {
+  return fmt.Sprintf("%v: %s = %s cannot become feasible", ErrInfeasible, e.Row, e.Value)
+}
+
+func (e *InfeasibleError) Is(target error) bool {
+  return target == ErrInfeasible
+}
+
+// UnboundedError names a variable of the problem that improves the objective
+// without any row to limit it. A variable held in substituted form, such as a
+// free x1 = x1+ - x1-, decreases when the column of x1- is the one unbounded.
+type UnboundedError struct {
+  Column string
+  Decreasing bool
+}
+
+func (e *UnboundedError) Error() string {
+  direction := "increase"
+  if e.Decreasing {
+    direction = "decrease"
+  }
+  return fmt.Sprintf("%v: %s can %s without limit", ErrUnbounded, e.Column, direction)
+}
+
+func (e *UnboundedError) Is(target error) bool {
+  return target == ErrUnbounded
+}
+
+// IterationLimitError reports the phase that ran out of iterations
+type IterationLimitError struct {
+  Phase string
+  Limit int
+}
+
+func (e *IterationLimitError) Error() string {
+  return fmt.Sprintf("%v: %s stopped after %d iterations", ErrIterationLimit, e.Phase, e.Limit)
+}
+
+func (e *IterationLimitError) Is(target error) bool {
+  return target == ErrIterationLimit
+}
+
+// NumericError reports a pivot that failed in the arithmetic, such as a zero
+// pivot element or an overflow. Err is the cause, for example
+// fraction.ErrDivisionByZero.
+type NumericError struct {
+  Row, Col int // Pivot element
+  Err error
+}
+
+func (e *NumericError) Error() string {
+  return fmt.Sprintf("%v: pivot (%d, %d): %v", ErrNumeric, e.Row, e.Col, e.Err)
+}
+
+func (e *NumericError) Is(target error) bool {
+  return target == ErrNumeric
+}
+
+func (e *NumericError) Unwrap() error {
+  return e.Err
+}
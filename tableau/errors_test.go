@@ -0,0 +1,95 @@
+package tableau_test
+
+import (
+  "errors"
+  "fmt"
+  "testing"
+
+  fr "simplex/fraction"
+  "simplex/parser"
+  tb "simplex/tableau"
+)
+
+func TestErrorsIs(t *testing.T) {
+  sentinels := []error{tb.ErrInfeasible, tb.ErrUnbounded, tb.ErrIterationLimit, tb.ErrNumeric}
+  tests := []struct {
+    err error
+    want error
+  }{
+    {&tb.InfeasibleError{Row: "W", Value: "-1"}, tb.ErrInfeasible},
+    {&tb.UnboundedError{Column: "x1"}, tb.ErrUnbounded},
+    {&tb.IterationLimitError{Phase: "Phase II", Limit: 10}, tb.ErrIterationLimit},
+    {&tb.NumericError{Row: 0, Col: 1, Err: fr.ErrDivisionByZero}, tb.ErrNumeric},
+  }
+  for _, tt := range tests {
+    wrapped := fmt.Errorf("solving: %w", tt.err)
+    for _, target := range sentinels {
+      if got := errors.Is(wrapped, target); got != (target == tt.want) {
+        t.Errorf("errors.Is(%v, %v) = %v", tt.err, target, got)
+      }
+    }
+  }
+
+  numeric := &tb.NumericError{Row: 0, Col: 1, Err: fr.ErrDivisionByZero}
+  if !errors.Is(numeric, fr.ErrDivisionByZero) {
+    t.Errorf("%v does not unwrap to its cause", numeric)
+  }
+}
+
+func TestExchangeCheckedZeroPivot(t *testing.T) {
+  p, err := parser.ParseProblem("x1 + x2", []string{"x1 + x2 <= 4", "x2 + x3 <= 2"}, true)
+  if err != nil {
+    t.Fatal(err)
+  }
+  tab := parser.ConvertToTableau[fr.Fraction](p)
+  // x1 is not in the second row
+  err = tab.ExchangeChecked(1, 0)
+  var numeric *tb.NumericError
+  if !errors.As(err, &numeric) || numeric.Row != 1 || numeric.Col != 0 {
+    t.Fatalf("err = %v, want a NumericError at (1, 0)", err)
+  }
+  if err := tab.ExchangeChecked(0, len(tab.ColNames)-1); err == nil || errors.Is(err, tb.ErrNumeric) {
+    t.Errorf("pivot on const: err = %v, want a bad pivot", err)
+  }
+}
+
+func TestUnboundedColumn(t *testing.T) {
+  tests := []struct {
+    name string
+    max bool
+    objective string
+    constraints []string
+    column string
+    decreasing bool
+  }{
+    {"nonnegative", true, "x1 + x2", []string{"x1 - x2 <= 3"}, "x2", false},
+    {"free", false, "x1 + x2", []string{"x1 free", "x2 <= 3"}, "x1", true},
+    {"non-positive", false, "x1 + x2", []string{"x1 <= 0", "x2 <= 3"}, "x1", true},
+    {"free increasing", true, "x1", []string{"x1 free", "x1 - x2 >= -5"}, "x1", false},
+  }
+  for _, tt := range tests {
+    t.Run(tt.name, func(t *testing.T) {
+      p, err := parser.ParseProblem(tt.objective, tt.constraints, tt.max)
+      if err != nil {
+        t.Fatal(err)
+      }
+      tab := parser.ConvertToTableau[fr.Fraction](p)
+      _, err = drive(&tab)
+      var unbounded *tb.UnboundedError
+      if !errors.As(err, &unbounded) {
+        t.Fatalf("err = %v, want an UnboundedError", err)
+      }
+      if unbounded.Column != tt.column || unbounded.Decreasing != tt.decreasing {
+        t.Errorf("%v, want %s with decreasing %v", err, tt.column, tt.decreasing)
+      }
+    })
+  }
+}
+
+func TestStatusString(t *testing.T) {
+  for status, want := range map[tb.Status]string{tb.Optimal: "optimal", tb.Infeasible: "infeasible", tb.Unbounded: "unbounded", tb.IterationLimit: "iteration limit", tb.Failed: "failed"} {
+    if got := status.String(); got != want {
+      t.Errorf("Status(%d) = %s, want %s", int(status), got, want)
+    }
+  }
+}
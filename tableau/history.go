@@ -0,0 +1,224 @@
+package tableau
+
+import (
+  "encoding/json"
+  "fmt"
+  "io"
+  "slices"
+
+  "simplex/field"
+)
+
+// Entry is a step of a History: the tableau before and after the exchange of
+// row Step.Row and column Step.Col, the bound flip of column Step.Col, or the
+// change between two phases, see IsPhaseChange
+type Entry[T field.Element[T]] struct {
+  Step Step
+  Before, After Tableau[T]
+}
+
+// History records the pivots of a run as copies of the tableau taken with
+// Copy, so that the run can be walked through, replayed and exported after
+// it ended. It watches the tableau as its Tracer. When a phase starts from a
+// tableau other than the last one recorded, as after EndPhase drops the
+// auxiliary row and the artificial variables, it records that change as an
+// entry too, so that each entry starts where the one before it ended.
+type History[T field.Element[T]] struct {
+  Initial Tableau[T] // The tableau the run started from
+  Entries []Entry[T]
+  Status Status // Outcome of the run
+  Err error
+
+  t *Tableau[T] // Tableau being recorded
+  before Tableau[T] // Copy taken at OnPivotChosen
+  pos int // Entries stepped over by Forward and Back
+}
+
+// NewHistory returns a History that records the runs of t, having made it
+// t.Tracer
+func NewHistory[T field.Element[T]](t *Tableau[T]) *History[T] {
+  h := &History[T]{t: t}
+  h.Initial = h.copy()
+  t.Tracer = h
+  return h
+}
+
+// copy returns a copy of the recorded tableau that is detached from the
+// tracer and the pivot rule, which a replay must not touch
+func (h *History[T]) copy() Tableau[T] {
+  c := h.t.Copy()
+  c.Tracer = nil
+  c.Rule = nil
+  return c
+}
+
+func (h *History[T]) OnInit(*Snapshot) {
+  h.Initial = h.copy()
+  h.Entries = nil
+  h.pos = 0
+}
+
+func (h *History[T]) OnPhaseChange(phase string, _ *Snapshot) {
+  last := h.last()
+  if h.t.equal(last) {
+    return
+  }
+  step := Step{Phase: phase, Row: -1, Col: -1}
+  h.Entries = append(h.Entries, Entry[T]{Step: step, Before: *last, After: h.copy()})
+}
+
+// last returns the tableau the recorded steps end with
+func (h *History[T]) last() *Tableau[T] {
+  if len(h.Entries) == 0 {
+    return &h.Initial
+  }
+  return &h.Entries[len(h.Entries)-1].After
+}
+
+func (h *History[T]) OnPivotChosen(Step, *Snapshot) {
+  h.before = h.copy()
+}
+
+func (h *History[T]) OnTransform(step Step, _ *Snapshot) {
+  h.Entries = append(h.Entries, Entry[T]{Step: step, Before: h.before, After: h.copy()})
+}
+
+func (h *History[T]) OnCycle(Cycle, *Snapshot) {}
+
+func (h *History[T]) OnTerminate(status Status, err error, _ *Snapshot) {
+  h.Status = status
+  h.Err = err
+}
+
+// Position returns the number of entries stepped over, from 0 at Initial to
+// len(Entries) at the end of the run
+func (h *History[T]) Position() int {
+  return h.pos
+}
+
+// Current returns the tableau at the current position: Initial, or the
+// After of the last entry stepped over
+func (h *History[T]) Current() *Tableau[T] {
+  if h.pos == 0 {
+    return &h.Initial
+  }
+  return &h.Entries[h.pos-1].After
+}
+
+// Forward steps over the next entry and returns it. It returns false at the
+// end of the run.
+func (h *History[T]) Forward() (Entry[T], bool) {
+  if h.pos == len(h.Entries) {
+    return Entry[T]{}, false
+  }
+  h.pos++
+  return h.Entries[h.pos-1], true
+}
+
+// Back steps back over the previous entry, undoing it, and returns it. It
+// returns false at Initial.
+func (h *History[T]) Back() (Entry[T], bool) {
+  if h.pos == 0 {
+    return Entry[T]{}, false
+  }
+  h.pos--
+  return h.Entries[h.pos], true
+}
+
+// Replay runs the recorded steps again on a copy of Initial, in order: Flip
+// for a bound flip, EndPhase for the end of Phase I or Big-M,
+// ExchangeChecked in the dual simplex method and Apply otherwise. A phase
+// change that EndPhase did not make, such as a row added with AddRow, is
+// taken as recorded. visit, if not nil, is called with each entry and the
+// result. Replay returns an error at the first result that differs from the
+// recorded After, which means the run cannot be reproduced from its steps.
+func (h *History[T]) Replay(visit func(e Entry[T], t *Tableau[T])) error {
+  t := h.Initial.Copy()
+  for i, e := range h.Entries {
+    var err error
+    switch {
+    case IsFlip(e.Step.Row, e.Step.Col):
+      t.Flip(e.Step.Col)
+    case IsPhaseChange(e.Step.Row, e.Step.Col):
+      if !t.equal(&e.Before) {
+        return fmt.Errorf("tableau: replaying step %d: %s starts from a different tableau", i+1, e.Step.Phase)
+      }
+      if t.phaseOne || t.bigM {
+        err = t.EndPhase()
+      } else {
+        t = e.After.Copy()
+      }
+    case e.Step.Phase == "Dual":
+      err = t.ExchangeChecked(e.Step.Row, e.Step.Col)
+    default:
+      err = t.Apply(e.Step.Row, e.Step.Col)
+    }
+    if err != nil {
+      return fmt.Errorf("tableau: replaying step %d: %w", i+1, err)
+    }
+    if !t.equal(&e.After) {
+      return fmt.Errorf("tableau: replaying step %d (%s) gives a different tableau", i+1, describe(e.Step))
+    }
+    if visit != nil {
+      visit(e, &t)
+    }
+  }
+  return nil
+}
+
+// describe names the change a step makes, for the errors of Replay
+func describe(step Step) string {
+  if IsPhaseChange(step.Row, step.Col) {
+    return "start of " + step.Phase
+  }
+  return step.Entering
+}
+
+// equal reports whether t and u hold the same table under the same names
+func (t *Tableau[T]) equal(u *Tableau[T]) bool {
+  if !slices.Equal(t.RowNames, u.RowNames) || !slices.Equal(t.ColNames, u.ColNames) {
+    return false
+  }
+  if t.phaseOne != u.phaseOne || t.bigM != u.bigM || len(t.flipped) != len(u.flipped) {
+    return false
+  }
+  for v := range t.flipped {
+    if !u.flipped[v] {
+      return false
+    }
+  }
+  for i := range t.Table {
+    for j := range t.Table[i] {
+      if t.Table[i][j].Cmp(u.Table[i][j]) != 0 {
+        return false
+      }
+    }
+  }
+  return true
+}
+
+// WriteJSON exports the history to w as JSON: the initial tableau, each
+// pivot with the tableaux before and after it, and the outcome
+func (h *History[T]) WriteJSON(w io.Writer) error {
+  type entry struct {
+    Step Step `json:"step"`
+    Before *Snapshot `json:"before"`
+    After *Snapshot `json:"after"`
+  }
+  out := struct {
+    Initial *Snapshot `json:"initial"`
+    Entries []entry `json:"entries"`
+    Status string `json:"status"`
+    Err string `json:"error,omitempty"`
+  }{Initial: h.Initial.Snapshot(), Entries: []entry{}, Status: h.Status.String()}
+  for _, e := range h.Entries {
+    out.Entries = append(out.Entries, entry{Step: e.Step, Before: e.Before.Snapshot(), After: e.After.Snapshot()})
+  }
+  if h.Err != nil {
+    out.Err = h.Err.Error()
+  }
+
+  enc := json.NewEncoder(w)
+  enc.SetIndent("", "  ")
+  return enc.Encode(out)
+}
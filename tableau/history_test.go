@@ -0,0 +1,211 @@
+package tableau_test
+
+import (
+  "bytes"
+  "context"
+  "encoding/json"
+  "errors"
+  "testing"
+
+  fr "simplex/fraction"
+  "simplex/parser"
+  "simplex/simplex"
+  tb "simplex/tableau"
+)
+
+// record solves max or min objective subject to constraints with simplex.Run
+// under a History
+func record(t *testing.T, max bool, objective string, constraints []string, opts simplex.Options) (*tb.Tableau[fr.Fraction], *tb.History[fr.Fraction]) {
+  t.Helper()
+  p, err := parser.ParseProblem(objective, constraints, max)
+  if err != nil {
+    t.Fatal(err)
+  }
+  tab := parser.ConvertToTableau[fr.Fraction](p)
+  h := tb.NewHistory(&tab)
+  if _, err := simplex.Run(context.Background(), &tab, opts); err != nil {
+    t.Fatal(err)
+  }
+  return &tab, h
+}
+
+// chained checks that each entry of h starts from the tableau the one before
+// it ended with
+func chained(t *testing.T, h *tb.History[fr.Fraction]) {
+  t.Helper()
+  last := h.Initial.Snapshot().String()
+  for i, e := range h.Entries {
+    if got := e.Before.Snapshot().String(); got != last {
+      t.Errorf("entry %d starts from\n%s\nnot from\n%s", i+1, got, last)
+    }
+    last = e.After.Snapshot().String()
+  }
+}
+
+func TestReplay(t *testing.T) {
+  equality := []string{"x1 + x2 + x3 >= 4", "x1 + 2x2 <= 6", "x3 <= 3", "x1 - x3 = 1"}
+  tests := []struct {
+    name string
+    max bool
+    objective string
+    constraints []string
+    opts simplex.Options
+    phases int // Phase changes recorded
+  }{
+    {"two-phase", false, "3x1 + 2x2 + 4x3", equality, simplex.Options{}, 1},
+    {"Big-M", false, "3x1 + 2x2 + 4x3", equality, simplex.Options{BigM: true}, 1},
+    {"bound flip", true, "2x1 + x2", []string{"x1 + x2 <= 4", "x1 <= 3"}, simplex.Options{}, 0},
+    {"Bland", true, "3/4x4 - 20x5 + 1/2x6 - 6x7", []string{"1/4x4 - 8x5 - x6 + 9x7 <= 0", "1/2x4 - 12x5 - 1/2x6 + 3x7 <= 0", "x6 + x8 <= 1"}, simplex.Options{Rule: "bland"}, 0},
+  }
+  for _, tt := range tests {
+    t.Run(tt.name, func(t *testing.T) {
+      tab, h := record(t, tt.max, tt.objective, tt.constraints, tt.opts)
+      if h.Status != tb.Optimal || h.Err != nil {
+        t.Fatalf("recorded %v, %v", h.Status, h.Err)
+      }
+      phases := 0
+      for _, e := range h.Entries {
+        if tb.IsPhaseChange(e.Step.Row, e.Step.Col) {
+          phases++
+        }
+      }
+      if phases != tt.phases {
+        t.Errorf("%d phase changes recorded, want %d", phases, tt.phases)
+      }
+      chained(t, h)
+
+      steps := 0
+      var final *tb.Tableau[fr.Fraction]
+      err := h.Replay(func(e tb.Entry[fr.Fraction], t *tb.Tableau[fr.Fraction]) {
+        steps++
+        final = t
+      })
+      if err != nil {
+        t.Fatal(err)
+      }
+      if steps != len(h.Entries) {
+        t.Errorf("Replay visited %d steps of %d", steps, len(h.Entries))
+      }
+      if final != nil && final.Snapshot().String() != tab.Snapshot().String() {
+        t.Errorf("Replay ends with\n%v\nnot with\n%v", final.Snapshot(), tab.Snapshot())
+      }
+    })
+  }
+}
+
+// TestReplayDual records a dual simplex run after a cut was added to the
+// solved tableau, which the History keeps as a phase change
+func TestReplayDual(t *testing.T) {
+  tab, h := record(t, true, "3x1 + 2x2", []string{"x1 + x2 <= 4", "x1 + 3x2 <= 6"}, simplex.Options{})
+  if err := tab.AddRow("c1", cut(t, tab, fr.New(3, 1))); err != nil {
+    t.Fatal(err)
+  }
+  if status, err := tab.DualSimplex(10); status != tb.Optimal {
+    t.Fatalf("DualSimplex = %v, %v", status, err)
+  }
+  chained(t, h)
+  last := h.Entries[len(h.Entries)-1]
+  if last.Step.Phase != "Dual" {
+    t.Errorf("last step %+v, want a dual pivot", last.Step)
+  }
+  if err := h.Replay(nil); err != nil {
+    t.Fatal(err)
+  }
+}
+
+func TestReplayMismatch(t *testing.T) {
+  _, h := record(t, true, "3x1 + 2x2", []string{"x1 + x2 <= 4", "x1 + 3x2 <= 6"}, simplex.Options{})
+  h.Entries[0].After.Table[0][0] = fr.New(99, 1)
+  if err := h.Replay(nil); err == nil {
+    t.Error("Replay accepts a tampered entry")
+  }
+}
+
+func TestHistoryWalk(t *testing.T) {
+  _, h := record(t, false, "3x1 + 2x2 + 4x3", []string{"x1 + x2 + x3 >= 4", "x1 + 2x2 <= 6", "x3 <= 3", "x1 - x3 = 1"}, simplex.Options{})
+  if _, ok := h.Back(); ok || h.Current() != &h.Initial {
+    t.Fatal("Back at the start")
+  }
+  for i := range h.Entries {
+    e, ok := h.Forward()
+    if !ok || h.Position() != i+1 {
+      t.Fatalf("Forward %d: %v at %d", i+1, ok, h.Position())
+    }
+    if h.Current().Snapshot().String() != e.After.Snapshot().String() {
+      t.Errorf("Current after step %d is not its After", i+1)
+    }
+  }
+  if _, ok := h.Forward(); ok {
+    t.Error("Forward past the end")
+  }
+  e, ok := h.Back()
+  if !ok || e.Step != h.Entries[len(h.Entries)-1].Step || h.Position() != len(h.Entries)-1 {
+    t.Errorf("Back gives %+v at %d", e.Step, h.Position())
+  }
+
+  var buf bytes.Buffer
+  if err := h.WriteJSON(&buf); err != nil {
+    t.Fatal(err)
+  }
+  var out struct {
+    Entries []json.RawMessage `json:"entries"`
+    Status string `json:"status"`
+  }
+  if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+    t.Fatal(err)
+  }
+  if len(out.Entries) != len(h.Entries) || out.Status != "optimal" {
+    t.Errorf("JSON has %d entries and status %q", len(out.Entries), out.Status)
+  }
+}
+
+// TestHistoryFailed checks that a run stopped by its context ends the
+// History with Failed and the error
+func TestHistoryFailed(t *testing.T) {
+  p, err := parser.ParseProblem("3x1 + 2x2", []string{"x1 + x2 <= 4", "x1 + 3x2 <= 6"}, true)
+  if err != nil {
+    t.Fatal(err)
+  }
+  tab := parser.ConvertToTableau[fr.Fraction](p)
+  h := tb.NewHistory(&tab)
+  ctx, cancel := context.WithCancel(context.Background())
+  cancel()
+  if _, err := simplex.Run(ctx, &tab, simplex.Options{}); !errors.Is(err, context.Canceled) {
+    t.Fatalf("err = %v, want context.Canceled", err)
+  }
+  if h.Status != tb.Failed || !errors.Is(h.Err, context.Canceled) {
+    t.Errorf("recorded %v, %v, want failed with context.Canceled", h.Status, h.Err)
+  }
+}
+
+// TestCopy checks that a copy pivots, flips and takes bounds without
+// touching the original
+func TestCopy(t *testing.T) {
+  p, err := parser.ParseProblem("2x1 + x2", []string{"x1 + x2 <= 4", "x1 <= 3", "x2 >= 1", "x3 free", "x3 - x1 <= 2"}, true)
+  if err != nil {
+    t.Fatal(err)
+  }
+  tab := parser.ConvertToTableau[fr.Fraction](p)
+  before := tab.Snapshot().String()
+  values := tab.GetSolution()
+
+  c := tab.Copy()
+  c.SetUpper("x2", fr.New(7, 1))
+  c.Recover("x1", fr.New(5, 1), map[string]fr.Fraction{"x1": fr.New(1, 1)})
+  c.Flip(0)
+  if _, err := drive(&c); err != nil {
+    t.Fatal(err)
+  }
+
+  if _, ok := tab.Upper("x2"); ok {
+    t.Error("SetUpper on the copy gives the original a bound")
+  }
+  if got := tab.Snapshot().String(); got != before {
+    t.Errorf("original changed to\n%s", got)
+  }
+  for v, x := range tab.GetSolution() {
+    if x.Cmp(values[v]) != 0 {
+      t.Errorf("%s = %v in the original, was %v", v, x, values[v])
+    }
+  }
+}
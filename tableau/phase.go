@@ -0,0 +1,181 @@
+package tableau
+
+import (
+  "fmt"
+  "slices"
+)
+
+// Status is the outcome of a run of the simplex method
+type Status int
+
+const (
+  Optimal Status = iota
+  Infeasible
+  Unbounded
+  IterationLimit
+  Failed // The run stopped on an error, such as a failure of the arithmetic
+)
+
+func (s Status) String() string {
+  switch s {
+  case Optimal:
+    return "optimal"
+  case Infeasible:
+    return "infeasible"
+  case Unbounded:
+    return "unbounded"
+  case IterationLimit:
+    return "iteration limit"
+  case Failed:
+    return "failed"
+  }
+  return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// StartPhaseOne sets up Phase I for a tableau whose rows named in artificial
+// hold artificial variables. It appends the row of W = -(sum of the
+// artificial variables), which is the negated sum of those rows and is
+// maximised by Pivot until EndPhase.
+func (t *Tableau[T]) StartPhaseOne(artificial []string) {
+  t.artificial = make(map[string]bool, len(artificial))
+  for _, name := range artificial {
+    t.artificial[name] = true
+  }
+
+  w := make([]T, len(t.Table[0]))
+  for i := 0; i < t.rows(); i++ {
+    if t.artificial[t.RowNames[i]] {
+      for j := range w {
+        w[j] = w[j].Sub(t.Table[i][j])
+      }
+    }
+  }
+
+  t.Table = append(t.Table, w)
+  t.RowNames = append(t.RowNames, "W")
+  t.phaseOne = true
+}
+
+// InPhaseOne reports whether the tableau still carries the Phase I objective
+func (t *Tableau[T]) InPhaseOne() bool {
+  return t.phaseOne
+}
+
+// Apply carries out the pivot (r, s) chosen by Pivot with ExchangeChecked.
+// A leaving variable that leaves at its upper bound has its row complemented
+// first. An artificial variable that leaves the basis is dropped with its
+// column, as it never enters again.
+func (t *Tableau[T]) Apply(r, s int) error {
+  if r >= 0 && r < t.rows() && s >= 0 && s < len(t.Table[0])-1 && t.Table[r][s].Sign() < 0 {
+    if _, ok := t.upper[t.RowNames[r]]; ok {
+      t.flipRow(r)
+    }
+  }
+  if err := t.ExchangeChecked(r, s); err != nil {
+    return err
+  }
+  t.dropArtificial(s)
+  return nil
+}
+
+// dropArtificial removes column s after a pivot if it holds an artificial
+// variable
+func (t *Tableau[T]) dropArtificial(s int) {
+  if t.artificial[t.ColNames[s]] {
+    t.removeCol(s)
+  }
+}
+
+// PhaseDone reports whether the current phase needs no more pivots: t is
+// optimal or, in the Big-M method, no artificial variable is basic
+func (t *Tableau[T]) PhaseDone() bool {
+  if t.bigM && !t.artificialBasic() {
+    return true
+  }
+  return t.IsOptimal()
+}
+
+// Unbounded returns, once Pivot finds no pivot, an *UnboundedError if the
+// objective of the current phase is unbounded, and nil otherwise. It is not
+// when t is optimal, nor in Phase I, where W cannot exceed
+// zero, nor in the Big-M method while an artificial variable is above zero:
+// then the part in M is optimal and EndPhase reports the infeasibility.
+func (t *Tableau[T]) Unbounded() error {
+  if t.IsOptimal() || t.phaseOne || t.bigM && t.artificialPositive() >= 0 {
+    return nil
+  }
+  for _, j := range t.Candidates() {
+    if _, ratio := t.RatioTest(j); ratio.IsInf() {
+      v, decreasing := t.origin(t.ColNames[j])
+      return &UnboundedError{Column: v, Decreasing: decreasing}
+    }
+  }
+  return nil
+}
+
+// EndPhase ends Phase I or the Big-M method once PhaseDone, or once Pivot
+// finds no pivot and Unbounded is nil. It returns an *InfeasibleError when
+// an artificial variable cannot reach zero, and otherwise removes the
+// artificial variables and the auxiliary row. Outside those phases it does
+// nothing.
+func (t *Tableau[T]) EndPhase() error {
+  n := len(t.Table[0])
+  switch {
+  case t.phaseOne:
+    w := len(t.Table) - 1
+    if t.Table[w][n-1].Sign() < 0 {
+      return &InfeasibleError{Row: t.RowNames[w], Value: t.Table[w][n-1].String()}
+    }
+    t.endArtificial()
+  case t.bigM:
+    if i := t.artificialPositive(); i >= 0 {
+      return &InfeasibleError{Row: t.RowNames[i], Value: t.Table[i][n-1].String()}
+    }
+    t.endArtificial()
+  }
+  return nil
+}
+
+// endArtificial pivots out the artificial variables that are still basic at
+// level zero, replacing each one by any variable with a nonzero entry in its
+// row or dropping the row when the constraint is redundant. It then removes
+// the auxiliary objective row, leaving F as the objective.
+func (t *Tableau[T]) endArtificial() {
+  n := len(t.Table[0])
+  for i := 0; i < t.rows(); i++ {
+    if !t.artificial[t.RowNames[i]] {
+      continue
+    }
+    s := -1
+    for j := 0; j < n-1; j++ {
+      if !t.Table[i][j].IsZero() {
+        s = j
+        break
+      }
+    }
+    if s == -1 {
+      // The constraint is redundant
+      t.removeRow(i)
+      i--
+      continue
+    }
+    t.Exchange(i, s)
+    t.removeCol(s)
+  }
+
+  t.removeRow(len(t.Table) - 1)
+  t.phaseOne = false
+  t.bigM = false
+}
+
+func (t *Tableau[T]) removeRow(i int) {
+  t.Table = slices.Delete(t.Table, i, i+1)
+  t.RowNames = slices.Delete(t.RowNames, i, i+1)
+}
+
+func (t *Tableau[T]) removeCol(j int) {
+  for i := range t.Table {
+    t.Table[i] = slices.Delete(t.Table[i], j, j+1)
+  }
+  t.ColNames = slices.Delete(t.ColNames, j, j+1)
+}
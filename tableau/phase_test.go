@@ -0,0 +1,143 @@
+package tableau_test
+
+import (
+  "errors"
+  "testing"
+
+  fr "simplex/fraction"
+  "simplex/parser"
+  tb "simplex/tableau"
+)
+
+// lps are linear programs with known optima. values lists the value of some
+// variables at the optimum, which is unique for them.
+var lps = []struct {
+  name string
+  max bool
+  objective string
+  constraints []string
+  want string // Optimal objective
+  values map[string]string
+  err error // Expected outcome instead of an optimum
+}{
+  {"two constraints", true, "2x1 + x2", []string{"3x1 + x2 <= 4", "x1 + 3x2 <= 5"}, "25/8", map[string]string{"x1": "7/8", "x2": "11/8"}, nil},
+  {"surplus rows", false, "2x1 + 3x2", []string{"x1 + x2 >= 4", "x1 + 3x2 >= 6"}, "9", map[string]string{"x1": "3", "x2": "1"}, nil},
+  {"equality", false, "3x1 + 2x2 + 4x3", []string{"x1 + x2 + x3 >= 4", "x1 + 2x2 <= 6", "x3 <= 3", "x1 - x3 = 1"}, "10", map[string]string{"x1": "4/3", "x2": "7/3", "x3": "1/3"}, nil},
+  {"redundant equality", true, "x1 + 2x2", []string{"x1 + x2 = 2", "2x1 + 2x2 = 4"}, "4", map[string]string{"x1": "0", "x2": "2"}, nil},
+  {"upper bounds", true, "3x1 + 5x2", []string{"x1 <= 4", "2x2 <= 12", "3x1 + 2x2 <= 18"}, "36", map[string]string{"x1": "2", "x2": "6"}, nil},
+  {"bound flip", true, "2x1 + x2", []string{"x1 + x2 <= 4", "x1 <= 3"}, "7", map[string]string{"x1": "3", "x2": "1"}, nil},
+  {"lower bound", false, "x1 + x2", []string{"x1 >= 2", "x1 + x2 >= 3", "x2 <= 5"}, "3", nil, nil},
+  {"free", false, "x1 + 2x2", []string{"x1 free", "x1 + x2 >= -2"}, "-2", map[string]string{"x1": "-2", "x2": "0"}, nil},
+  {"non-positive", true, "x1 + 2x4", []string{"x4 <= 0", "x1 + x4 = 1", "x1 >= 3"}, "-1", map[string]string{"x1": "3", "x4": "-2"}, nil},
+  // Beale's example, which cycles under Dantzig's rule without a guard
+  {"degenerate", true, "3/4x4 - 20x5 + 1/2x6 - 6x7", []string{"1/4x4 - 8x5 - x6 + 9x7 <= 0", "1/2x4 - 12x5 - 1/2x6 + 3x7 <= 0", "x6 + x8 <= 1"}, "5/4", map[string]string{"x4": "1", "x6": "1"}, nil},
+  {"infeasible", true, "x1 + x2", []string{"x1 + x2 >= 5", "x1 + x2 <= 3"}, "", nil, tb.ErrInfeasible},
+  {"infeasible equality", false, "x1", []string{"x1 + x2 = 2", "x1 + x2 >= 3"}, "", nil, tb.ErrInfeasible},
+  {"empty bound", true, "x1", []string{"x1 >= 3", "x1 <= 1"}, "", nil, tb.ErrInfeasible},
+  {"unbounded", true, "x1 + x2", []string{"x1 - x2 <= 3"}, "", nil, tb.ErrUnbounded},
+  {"unbounded after Phase I", true, "x1", []string{"x1 - x2 >= 1"}, "", nil, tb.ErrUnbounded},
+}
+
+// drive runs t through its phases the way simplex.Run does, with a cycle
+// guard in each phase
+func drive(t *tb.Tableau[fr.Fraction]) (tb.Status, error) {
+  for {
+    guard := tb.NewCycleGuard(t)
+    for !t.PhaseDone() {
+      r, s := t.Pivot()
+      if tb.IsFlip(r, s) {
+        t.Flip(s)
+        continue
+      }
+      if !tb.IsPivotValid(r, s) {
+        if err := t.Unbounded(); err != nil {
+          return tb.Unbounded, err
+        }
+        break
+      }
+      entering, leaving := t.ColNames[s], t.RowNames[r]
+      if err := t.Apply(r, s); err != nil {
+        return tb.Failed, err
+      }
+      guard.After(t, entering, leaving)
+    }
+    if !t.InPhaseOne() && !t.InBigM() {
+      return tb.Optimal, nil
+    }
+    if err := t.EndPhase(); err != nil {
+      return tb.Infeasible, err
+    }
+  }
+}
+
+// solveLPs solves each of lps with the tableau that setup prepares
+func solveLPs(t *testing.T, setup func(t *tb.Tableau[fr.Fraction])) {
+  for _, lp := range lps {
+    t.Run(lp.name, func(t *testing.T) {
+      p, err := parser.ParseProblem(lp.objective, lp.constraints, lp.max)
+      if err != nil {
+        t.Fatal(err)
+      }
+      tab := parser.ConvertToTableau[fr.Fraction](p)
+      setup(&tab)
+      status, err := drive(&tab)
+      if !errors.Is(err, lp.err) {
+        t.Fatalf("status %v, err = %v, want %v", status, err, lp.err)
+      }
+      if lp.err != nil {
+        return
+      }
+
+      sol := tab.Solution(status)
+      if got := sol.Objective.String(); got != lp.want {
+        t.Errorf("objective = %s, want %s", got, lp.want)
+      }
+      for v, want := range lp.values {
+        if got := sol.Values[v].String(); got != want {
+          t.Errorf("%s = %s, want %s", v, got, want)
+        }
+      }
+      for _, eq := range p.Constraints {
+        if parser.Slack(eq, sol.Values).Sign() < 0 {
+          t.Errorf("%v is violated by %v", eq, sol.Values)
+        }
+      }
+    })
+  }
+}
+
+func TestPhaseOne(t *testing.T) {
+  solveLPs(t, func(*tb.Tableau[fr.Fraction]) {})
+}
+
+// TestEndPhase checks that EndPhase removes W and the artificial variables
+func TestEndPhase(t *testing.T) {
+  p, err := parser.ParseProblem("2x1 + 3x2", []string{"x1 + x2 >= 4", "x1 + 3x2 = 6"}, false)
+  if err != nil {
+    t.Fatal(err)
+  }
+  tab := parser.ConvertToTableau[fr.Fraction](p)
+  if !tab.InPhaseOne() || tab.RowNames[len(tab.RowNames)-1] != "W" {
+    t.Fatalf("rows %v, want Phase I with W last", tab.RowNames)
+  }
+  for !tab.PhaseDone() {
+    r, s := tab.Pivot()
+    if !tb.IsPivotValid(r, s) {
+      t.Fatalf("no pivot in Phase I of a feasible problem")
+    }
+    if err := tab.Apply(r, s); err != nil {
+      t.Fatal(err)
+    }
+  }
+  if err := tab.EndPhase(); err != nil {
+    t.Fatal(err)
+  }
+  if tab.InPhaseOne() || tab.RowNames[len(tab.RowNames)-1] != "F" {
+    t.Errorf("rows %v after EndPhase, want F last", tab.RowNames)
+  }
+  for _, v := range append(tab.RowNames, tab.ColNames...) {
+    if v[0] == 'a' {
+      t.Errorf("artificial variable %s is left in %v %v", v, tab.RowNames, tab.ColNames)
+    }
+  }
+}
@@ -0,0 +1,253 @@
+package tableau
+
+import (
+  "fmt"
+  "math/rand"
+  "strings"
+
+  "simplex/field"
+)
+
+// PivotRule chooses the pivot of a simplex iteration. Choose returns the row
+// and column to exchange, or -1, -1 when the objective is optimal or
+// unbounded. It returns -1 and the column when RatioTest finds that the
+// entering variable reaches its own upper bound first; Tableau.Flip then
+// moves the variable to that bound and the driver asks again.
+type PivotRule[T field.Element[T]] interface {
+  Choose(t *Tableau[T]) (int, int)
+}
+
+// RuleNames lists the names accepted by NewRule
+var RuleNames = []string{"dantzig", "bland", "steepest", "greatest", "random"}
+
+// NewRule returns the built-in rule called name, one of RuleNames. The
+// random rule is seeded with seed.
+func NewRule[T field.Element[T]](name string, seed int64) (PivotRule[T], error) {
+  switch strings.ToLower(name) {
+  case "dantzig":
+    return Dantzig[T]{}, nil
+  case "bland":
+    return &Bland[T]{}, nil
+  case "steepest":
+    return SteepestEdge[T]{}, nil
+  case "greatest":
+    return GreatestImprovement[T]{}, nil
+  case "random":
+    return NewRandom[T](seed), nil
+  }
+  return nil, fmt.Errorf("tableau: unknown pivot rule %q (want one of %s)", name, strings.Join(RuleNames, ", "))
+}
+
+// Dantzig enters the column with the most negative objective entry when
+// maximising, the most positive when minimising, and leaves by RatioTest
+type Dantzig[T field.Element[T]] struct{}
+
+func (Dantzig[T]) Choose(t *Tableau[T]) (int, int) {
+  n := len(t.Table[0]) // Number of columns
+
+  // For maximization: find most negative coefficient in objective function row
+  // For minimization: find most positive coefficient in objective function row
+  s := -1
+  var pivotValue BigM[T]
+
+  for j := 0; j < n-1; j++ { // Skip last column (constant)
+    c := t.cost(j)
+    if t.maximizing() && c.Sign() < 0 {
+      // For maximization, find most negative coefficient
+      if s == -1 || c.Cmp(pivotValue) < 0 {
+        s = j
+        pivotValue = c
+      }
+    } else if !t.maximizing() && c.Sign() > 0 {
+      // For minimization, find most positive coefficient
+      if s == -1 || c.Cmp(pivotValue) > 0 {
+        s = j
+        pivotValue = c
+      }
+    }
+  }
+
+  return t.enter(s)
+}
+
+// Bland enters the improving variable with the smallest index and, among
+// rows tied in the ratio test, lets the variable with the smallest index
+// leave. It never cycles. Variables are numbered in the order the rule first
+// sees them, columns before rows, so a Bland value must not be shared
+// between unrelated tableaux.
+type Bland[T field.Element[T]] struct {
+  order map[string]int
+}
+
+func (b *Bland[T]) Choose(t *Tableau[T]) (int, int) {
+  n := len(t.Table[0])
+  for _, name := range t.ColNames[:n-1] {
+    b.index(name)
+  }
+  for _, name := range t.RowNames[:t.rows()] {
+    b.index(name)
+  }
+
+  s := -1
+  for _, j := range t.Candidates() {
+    if s == -1 || b.index(t.ColNames[j]) < b.index(t.ColNames[s]) {
+      s = j
+    }
+  }
+  if s == -1 {
+    return -1, -1
+  }
+
+  r := -1
+  minRatio := field.Inf[T](1)
+  if u, ok := t.upper[t.ColNames[s]]; ok {
+    minRatio = field.Finite(u)
+  }
+  for i := 0; i < t.rows(); i++ {
+    if ratio, ok := t.limit(i, s); ok {
+      if c := ratio.Cmp(minRatio); c < 0 || c == 0 && r >= 0 && b.index(t.RowNames[i]) < b.index(t.RowNames[r]) {
+        r = i
+        minRatio = ratio
+      }
+    }
+  }
+  if minRatio.IsInf() {
+    return -1, -1 // No limiting row
+  }
+  return r, s
+}
+
+func (b *Bland[T]) index(name string) int {
+  if b.order == nil {
+    b.order = make(map[string]int)
+  }
+  i, ok := b.order[name]
+  if !ok {
+    i = len(b.order)
+    b.order[name] = i
+  }
+  return i
+}
+
+// SteepestEdge enters the column whose objective entry is largest relative
+// to the length of the column, comparing squares so that it stays exact
+type SteepestEdge[T field.Element[T]] struct{}
+
+func (SteepestEdge[T]) Choose(t *Tableau[T]) (int, int) {
+  cands := t.Candidates()
+  rates := t.rates(cands)
+
+  s := -1
+  var best T
+  for k, j := range cands {
+    norm := field.One[T]()
+    for i := 0; i < t.rows(); i++ {
+      norm = norm.Add(t.Table[i][j].Mul(t.Table[i][j]))
+    }
+    score := rates[k].Mul(rates[k]).Div(norm)
+    if s == -1 || score.Cmp(best) > 0 {
+      s = j
+      best = score
+    }
+  }
+  return t.enter(s)
+}
+
+// GreatestImprovement enters the column whose pivot improves the objective
+// the most, that is the largest step from RatioTest times the rate of
+// improvement
+type GreatestImprovement[T field.Element[T]] struct{}
+
+func (GreatestImprovement[T]) Choose(t *Tableau[T]) (int, int) {
+  cands := t.Candidates()
+  rates := t.rates(cands)
+
+  s, r := -1, -1
+  var best T
+  for k, j := range cands {
+    i, ratio := t.RatioTest(j)
+    step, finite := ratio.Value()
+    if !finite {
+      return -1, -1 // No limiting row
+    }
+    gain := step.Mul(rates[k])
+    if s == -1 || gain.Cmp(best) > 0 {
+      s, r = j, i
+      best = gain
+    }
+  }
+  return r, s
+}
+
+// Random enters a column chosen uniformly among the improving ones
+type Random[T field.Element[T]] struct {
+  rng *rand.Rand
+}
+
+// NewRandom returns a Random rule whose choices are fixed by seed
+func NewRandom[T field.Element[T]](seed int64) *Random[T] {
+  return &Random[T]{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (x *Random[T]) Choose(t *Tableau[T]) (int, int) {
+  cands := t.Candidates()
+  if len(cands) == 0 {
+    return -1, -1
+  }
+  return t.enter(cands[x.rng.Intn(len(cands))])
+}
+
+// Candidates returns the columns that may enter the basis: the nonbasic
+// variables whose objective entry shows that the objective improves. In the
+// Big-M method these are the columns that improve the part in M while there
+// are any.
+func (t *Tableau[T]) Candidates() []int {
+  n := len(t.Table[0])
+  var cands, inM []int
+  for j := 0; j < n-1; j++ {
+    c := t.cost(j)
+    if (t.maximizing() && c.Sign() < 0) || (!t.maximizing() && c.Sign() > 0) {
+      cands = append(cands, j)
+      if !c.M.IsZero() {
+        inM = append(inM, j)
+      }
+    }
+  }
+  if len(inM) > 0 {
+    return inM
+  }
+  return cands
+}
+
+// rates returns how fast the objective improves per unit of each candidate
+// column. In the Big-M method these are the parts in M while the candidates
+// have them, since they outweigh any number.
+func (t *Tableau[T]) rates(cands []int) []T {
+  rates := make([]T, len(cands))
+  for k, j := range cands {
+    c := t.cost(j)
+    v := c.C
+    if !c.M.IsZero() {
+      v = c.M
+    }
+    if t.maximizing() {
+      v = v.Neg()
+    }
+    rates[k] = v
+  }
+  return rates
+}
+
+// enter completes the pivot for entering column s with RatioTest
+func (t *Tableau[T]) enter(s int) (int, int) {
+  if s == -1 {
+    return -1, -1
+  }
+
+  r, ratio := t.RatioTest(s)
+  if ratio.IsInf() {
+    return -1, -1 // No limiting row
+  }
+  return r, s
+}
+
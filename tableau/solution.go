@@ -0,0 +1,53 @@
+package tableau
+
+import (
+  "simplex/field"
+)
+
+// Solution is the outcome of solving a tableau, in terms of the problem it
+// was built from
+type Solution[T field.Element[T]] struct {
+  Status Status
+  Objective T // Value of the objective function, for min and max alike
+  Values map[string]T // Value of each variable of the problem
+  Slacks []T // b - a·x of each "<=" constraint, a·x - b of each ">=" one, zero for "="
+  Basis Basis
+}
+
+// SetOrigin records the variables of the problem t was built from and the
+// slack or surplus variable of each of its constraints, "" for an "="
+// constraint, so that Solution can report them
+func (t *Tableau[T]) SetOrigin(variables, slacks []string) {
+  t.variables = variables
+  t.slacks = slacks
+}
+
+// Solution returns the solution at the current basis of t with the given
+// status. Objective, Values and Slacks are only set when status is Optimal.
+// Without SetOrigin, Values holds every variable of the tableau.
+func (t *Tableau[T]) Solution(status Status) *Solution[T] {
+  sol := &Solution[T]{Status: status, Basis: t.Basis()}
+  if status != Optimal {
+    return sol
+  }
+
+  values := t.GetSolution()
+  sol.Objective = values["objective"]
+  delete(values, "objective")
+  if t.variables == nil {
+    sol.Values = values
+    return sol
+  }
+
+  sol.Values = make(map[string]T, len(t.variables))
+  for _, v := range t.variables {
+    sol.Values[v] = values[v]
+  }
+  sol.Slacks = make([]T, len(t.slacks))
+  for i, v := range t.slacks {
+    if v != "" {
+      sol.Slacks[i] = values[v]
+    }
+  }
+  return sol
+}
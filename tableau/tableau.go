@@ -2,63 +2,70 @@ package tableau
 
 import (
   "fmt"
+  "io"
+  "maps"
+  "os"
+  "simplex/field"
   fr "simplex/fraction"
+  "simplex/linalg"
 )
 
-type Tableau struct {
-  Table [][]fr.Fraction
-  dirtX []bool
-  dirtY []bool
-  RowNames []string  // For slack variables (s1, s2, ..., F)
-  ColNames []string  // For decision variables (-x1, -x2, ..., const)
+// Tableau is a simplex tableau over the number type T, for example
+// fraction.Fraction, field.Rat or field.Float.
+type Tableau[T field.Element[T]] struct {
+  Table [][]T
+  RowNames []string  // Basic variables (s1, s2, ..., F), see Basis
+  ColNames []string  // Nonbasic variables (x1, x2, ..., const)
   IsMaximization bool // To track if we're maximizing or minimizing
+  Rule PivotRule[T] // Chooses the pivot in Pivot, Dantzig's rule if nil
+  Tracer Tracer // Receives the events of the drivers, if set
+  phaseOne bool // The last row is the Phase I objective W, F is above it
+  bigM bool // The last row holds the coefficients of M in the objective F above it
+  artificial map[string]bool // Artificial variables added for Phase I
+  upper map[string]T // Upper bounds of variables, see SetUpper
+  flipped map[string]bool // Variables replaced by their complement u - x
+  recover map[string]recovery[T] // Original variables held in substituted form
+  variables []string // Variables of the problem, see SetOrigin
+  slacks []string // Slack or surplus variable of each constraint, see SetOrigin
 }
 
-func (t *Tableau) ResetDirt() {
-  for i := range t.dirtX {
-    t.dirtX[i] = false
-  }
-  for i := range t.dirtY {
-    t.dirtX[i] = false
-  }
-}
-
-func (t *Tableau) PrintDirt() {
-  for i := range t.dirtX {
-    print(t.dirtX[i], " ")
-  }
-  println()
-  for i := range t.dirtY {
-    print(t.dirtX[i], " ")
-  }
-}
-
-func (t *Tableau) Copy() Tableau {
-  copyTable := make([][]fr.Fraction, len(t.Table))
+func (t *Tableau[T]) Copy() Tableau[T] {
+  copyTable := make([][]T, len(t.Table))
   for i := range t.Table {
-    copyTable[i] = make([]fr.Fraction, len(t.Table[i]))
+    copyTable[i] = make([]T, len(t.Table[i]))
     copy(copyTable[i], t.Table[i])
   }
 
-  copyDirtX := make([]bool, len(t.dirtX))
-  copy(copyDirtX, t.dirtX)
-
-  copyDirtY := make([]bool, len(t.dirtY))
-  copy(copyDirtY, t.dirtY)
-
   copyRowNames := make([]string, len(t.RowNames))
   copy(copyRowNames, t.RowNames)
 
   copyColNames := make([]string, len(t.ColNames))
   copy(copyColNames, t.ColNames)
 
-  return Tableau{
+  // The maps change as the copy pivots, so it gets its own
+  var copyRecover map[string]recovery[T]
+  if t.recover != nil {
+    copyRecover = make(map[string]recovery[T], len(t.recover))
+    for v, rec := range t.recover {
+      copyRecover[v] = recovery[T]{constant: rec.constant, terms: maps.Clone(rec.terms)}
+    }
+  }
+
+  return Tableau[T]{
     Table:          copyTable,
-    dirtX:          copyDirtX,
-    dirtY:          copyDirtY,
     RowNames:       copyRowNames,
     ColNames:       copyColNames,
     IsMaximization: t.IsMaximization,
+    Rule:           t.Rule,
+    Tracer:         t.Tracer,
+    phaseOne:       t.phaseOne,
+    bigM:           t.bigM,
+    artificial:     maps.Clone(t.artificial),
+    upper:          maps.Clone(t.upper),
+    flipped:        maps.Clone(t.flipped),
+    recover:        copyRecover,
+    variables:      t.variables,
+    slacks:         t.slacks,
   }
 }
 
@@ -66,17 +73,24 @@ func IsPivotValid(r, s int) bool {
   return r >= 0 && s >= 0
 }
 
-func (t *Tableau) Init(rows, cols int) {
+// IsFlip reports whether Pivot chose a bound flip of column s
+func IsFlip(r, s int) bool {
+  return r == -1 && s >= 0
+}
+
+// IsPhaseChange reports whether the Step of a History entry is the change of
+// the tableau between two phases rather than a pivot
+func IsPhaseChange(r, s int) bool {
+  return r == -1 && s == -1
+}
+
+func (t *Tableau[T]) Init(rows, cols int) {
   // Initialize the table without variable rows/columns
-  t.Table = make([][]fr.Fraction, rows)
+  t.Table = make([][]T, rows)
   for i := range t.Table {
-    t.Table[i] = make([]fr.Fraction, cols)
+    t.Table[i] = make([]T, cols)
   }
 
-  // Initialize tracking arrays
-  t.dirtX = make([]bool, cols)
-  t.dirtY = make([]bool, rows)
-  
   // Initialize variable names
   t.RowNames = make([]string, rows)
   t.ColNames = make([]string, cols)
@@ -89,7 +103,7 @@ func (t *Tableau) Init(rows, cols int) {
   
   // Default column names
   for j := 0; j < cols-1; j++ {
-    t.ColNames[j] = fmt.Sprintf("-x%d", j+1)
+    t.ColNames[j] = fmt.Sprintf("x%d", j+1)
   }
   t.ColNames[cols-1] = "const" // Last column is constants
   
@@ -97,223 +111,178 @@ func (t *Tableau) Init(rows, cols int) {
   t.IsMaximization = true
 }
 
-func (t *Tableau) isValidCell(i, j int) bool {
-  return !t.dirtX[j] && !t.dirtY[i] && t.Table[i][j].N != 0
+// Pivot chooses the pivot of the next simplex iteration with t.Rule, or
+// with Dantzig's rule when t.Rule is nil. It returns -1, -1 when the
+// objective is optimal or unbounded, so callers check Unbounded to tell the
+// two apart.
+//
+// With upper bounds it returns -1 and the column, see IsFlip, when the
+// entering variable reaches its own upper bound first; Flip then moves the
+// variable to that bound and the next call chooses again. When the leaving
+// variable leaves at its upper bound the pivot element is negative, and
+// Apply complements its row before the exchange.
+func (t *Tableau[T]) Pivot() (int, int) {
+  var rule PivotRule[T] = Dantzig[T]{}
+  if t.Rule != nil {
+    rule = t.Rule
+  }
+  return rule.Choose(t)
 }
 
-func (t *Tableau) Pivot() (int, int) {
-  m := len(t.Table)    // Number of rows
-  n := len(t.Table[0]) // Number of columns
-  
-  // For maximization: find most negative coefficient in objective function row
-  // For minimization: find most positive coefficient in objective function row
-  s := -1
-  pivotValue := fr.Fraction{N: 0, D: 1}
-  
-  for j := 0; j < n-1; j++ { // Skip last column (constant)
-    if !t.dirtX[j] {
-      if t.IsMaximization && t.Table[m-1][j].N < 0 {
-        // For maximization, find most negative coefficient
-        if s == -1 || t.Table[m-1][j].N < pivotValue.N {
-          s = j
-          pivotValue = t.Table[m-1][j]
-        }
-      } else if !t.IsMaximization && t.Table[m-1][j].N > 0 {
-        // For minimization, find most positive coefficient
-        if s == -1 || t.Table[m-1][j].N > pivotValue.N {
-          s = j
-          pivotValue = t.Table[m-1][j]
-        }
-      }
-    }
-  }
-  
-  if s == -1 {
-    return -1, -1
-  }
-  
-  // Find row with minimum ratio test (smallest positive ratio)
+// RatioTest finds the row that limits how far the variable of column s can
+// increase: the smallest const / coefficient over positive coefficients, and
+// with upper bounds the smallest distance to the bound over negative
+// coefficients. It returns -1 and +∞ when no row limits it, and -1 and the
+// upper bound of the variable when that bound comes first.
+func (t *Tableau[T]) RatioTest(s int) (int, field.Ext[T]) {
   r := -1
-  minRatio := fr.Fraction{N: 0, D: 0} // Initialize with "infinity"
-  
-  for i := 0; i < m-1; i++ { // Skip objective function row
-    if !t.dirtY[i] && t.Table[i][s].N > 0 {
-      ratio := fr.Div(t.Table[i][n-1], t.Table[i][s]) // const / coefficient
-      if r == -1 || (ratio.N > 0 && (minRatio.N <= 0 || 
-         (ratio.N * minRatio.D < minRatio.N * ratio.D))) {
-        r = i
-        minRatio = ratio
-      }
-    }
+  minRatio := field.Inf[T](1)
+  if u, ok := t.upper[t.ColNames[s]]; ok {
+    minRatio = field.Finite(u)
   }
-  
-  if r == -1 {
-    // No limiting constraint - unbounded solution
-    fmt.Println("Warning: Unbounded solution detected")
-    return -1, -1
+  for i := 0; i < t.rows(); i++ { // Skip objective function rows
+    if ratio, ok := t.limit(i, s); ok && ratio.Less(minRatio) {
+      r = i
+      minRatio = ratio
+    }
   }
-  
-  return r, s
+  return r, minRatio
 }
 
-func (t *Tableau) PivotForFeasibility() (int, int) {
-    n := len(t.Table[0]) // Number of columns
-    m := len(t.Table)    // Number of rows
-    
-    // Find row with negative RHS
-    r := -1
-    for i := 0; i < m-1; i++ { // Skip objective row
-        if t.Table[i][n-1].N < 0 && !t.dirtY[i] {
-            r = i
-            break
-        }
-    }
-    
-    if r == -1 {
-        // No negative RHS found
-        return -1, -1
-    }
-    
-    // Find column with negative coefficient in that row
-    s := -1
-    mostNegative := fr.Fraction{N: 0, D: 1}
-    
-    for j := 0; j < n-1; j++ { // Skip constant column
-        if t.Table[r][j].N < 0 && !t.dirtX[j] {
-            // Choose the most negative coefficient
-            if s == -1 || t.Table[r][j].N < mostNegative.N {
-                s = j
-                mostNegative = t.Table[r][j]
-            }
-        }
-    }
-    
-    return r, s
+// Transform returns a copy of t pivoted on (r, s); Exchange does the same in
+// place.
+func (t Tableau[T]) Transform(r, s int) Tableau[T] {
+  b := t.Copy()
+  b.Exchange(r, s)
+  return b
 }
 
-func (t *Tableau) MakeFeasible() bool {
-    fmt.Println("\nAttempting to make tableau feasible...")
-    
-    iteration := 1
-    for !t.IsFeasible() {
-        fmt.Printf("\n--- Feasibility Iteration %d ---\n", iteration)
-        
-        r, s := t.PivotForFeasibility()
-        if !IsPivotValid(r, s) {
-            fmt.Println("Failed to find appropriate pivot for feasibility. Problem may be infeasible.")
-            return false
-        }
-        
-        fmt.Printf("Pivoting on element at row %d, column %d (intersection of %s and %s)\n", 
-                  r, s, t.RowNames[r], t.ColNames[s])
-        *t = t.Transform(r, s)
-        
-        Print(t)
-        iteration++
-        
-        // Safety check to prevent infinite loops
-        if iteration > 20 {
-            fmt.Println("Warning: Maximum feasibility iterations reached. Process stopped.")
-            return false
-        }
-    }
-    
-    fmt.Println("Tableau is now feasible.")
-    return true
+// Exchange pivots t on (r, s) in place with linalg.ExchangeInPlace and swaps
+// the names of the pivot row and column
+func (t *Tableau[T]) Exchange(r, s int) {
+  linalg.ExchangeInPlace(t.Table, r, s)
+  t.RowNames[r], t.ColNames[s] = t.ColNames[s], t.RowNames[r]
 }
 
-func (t Tableau) Transform(r, s int) Tableau {
+// TransformChecked is Transform with validation. It reports a pivot outside
+// the constraint rows and variable columns, a zero pivot element, and any
+// invalid value (a zero denominator, NaN) that the exchange produces, leaving
+// t unchanged.
+func (t Tableau[T]) TransformChecked(r, s int) (Tableau[T], error) {
   b := t.Copy()
-  b.dirtY[r] = true
-  b.dirtX[s] = true
+  if err := b.ExchangeChecked(r, s); err != nil {
+    return t, err
+  }
+  return b, nil
+}
 
-  pivotElement := t.Table[r][s]
-  
-  b.Table[r][s] = fr.Div(fr.Fraction{N: 1, D: 1}, pivotElement)
-  
-  for j := 0; j < len(t.Table[0]); j++ {
-    if j != s {
-      b.Table[r][j] = fr.Div(t.Table[r][j], pivotElement)
-    }
+// ExchangeChecked is Exchange with the checks of TransformChecked. A bad
+// pivot leaves t unchanged; an invalid value is reported after the exchange.
+// Failures of the arithmetic are a *NumericError.
+func (t *Tableau[T]) ExchangeChecked(r, s int) error {
+  n := len(t.Table[0])
+  if r < 0 || r >= t.rows() || s < 0 || s >= n-1 {
+    return fmt.Errorf("tableau: pivot (%d, %d) is outside the table", r, s)
   }
-  
-  for i := 0; i < len(t.Table); i++ {
-    if i != r {
-      for j := 0; j < len(t.Table[0]); j++ {
-        if j != s {
-          b.Table[i][j] = fillElement(t, i, j, r, s)
+  if t.Table[r][s].IsZero() {
+    return &NumericError{Row: r, Col: s, Err: fr.ErrDivisionByZero}
+  }
+
+  t.Exchange(r, s)
+  var zero T
+  if _, ok := any(zero).(field.Checker); !ok {
+    return nil
+  }
+
+  // The exchange only writes the pivot row and column, and the cells where
+  // both of them are nonzero
+  for i := range t.Table {
+    if i != r && t.Table[i][s].IsZero() {
+      if err := t.check(r, s, i, s); err != nil {
+        return err
+      }
+      continue
+    }
+    for j := range t.Table[i] {
+      if i == r || j == s || !t.Table[r][j].IsZero() {
+        if err := t.check(r, s, i, j); err != nil {
+          return err
         }
       }
-      b.Table[i][s] = fr.Neg(fr.Div(t.Table[i][s], pivotElement))
     }
   }
+  return nil
+}
 
-  b.RowNames[r], b.ColNames[s] = b.ColNames[s], b.RowNames[r]
-  
-  return b
+// check reports an invalid value at (i, j) after the exchange on (r, s)
+func (t *Tableau[T]) check(r, s, i, j int) error {
+  if err := any(t.Table[i][j]).(field.Checker).Err(); err != nil {
+    return &NumericError{Row: r, Col: s, Err: fmt.Errorf("element at (%d, %d): %w", i, j, err)}
+  }
+  return nil
 }
 
-func fillElement(a Tableau, i, j, r, s int) fr.Fraction {
-  // Element transformation formula: (a_ij * a_rs - a_is * a_rj) / -a_rs
-  pivotElement := a.Table[r][s]
-  term1 := fr.Mul(a.Table[i][j], pivotElement)
-  term2 := fr.Mul(a.Table[i][s], a.Table[r][j])
-  result := fr.Div(fr.Sub(term1, term2), pivotElement)
-  return result
+// Print writes t to standard output, see Fprint
+func Print[T field.Element[T]](t *Tableau[T]) {
+  Fprint(os.Stdout, t)
 }
 
-func Print(a *Tableau) {
-  fmt.Println("Current Tableau:")
-  
-  fmt.Printf("%-10s", "")
-  for j := 0; j < len(a.Table[0]); j++ {
-    if j < len(a.Table[0]) - 1 {
-      fmt.Printf("%-10s", "-" + a.ColNames[j])
-    } else {
-      fmt.Printf("%-10s", a.ColNames[j])
-    }
-  }
-  fmt.Println()
-  
-  for i := 0; i < len(a.Table); i++ {
-    fmt.Printf("%-10s", a.RowNames[i])
-    for j := 0; j < len(a.Table[i]); j++ {
-      fr.Print(&a.Table[i][j], 10)
-    }
-    fmt.Println()
-  }
+// Fprint writes t to w as a table, with the basic variables down the left
+// and the nonbasic ones, negated, across the top
+func Fprint[T field.Element[T]](w io.Writer, t *Tableau[T]) {
+  t.Snapshot().Fprint(w)
 }
 
-func (a *Tableau) GetSolution() map[string]fr.Fraction {
-  solution := make(map[string]fr.Fraction)
-  m := len(a.Table)    // Number of rows
+func (a *Tableau[T]) GetSolution() map[string]T {
+  solution := make(map[string]T)
   n := len(a.Table[0]) // Number of columns
   
-  solution["objective"] = a.Table[m-1][n-1]
+  solution["objective"] = a.Table[a.rows()][n-1]
   
-  for i := 0; i < m-1; i++ {
-    for j := 0; j < n-1; j++ {
-      varName := a.RowNames[i]
-      solution[varName] = a.Table[i][n-1]
-    }
+  for i := 0; i < a.rows(); i++ {
+    varName := a.RowNames[i]
+    solution[varName] = a.Table[i][n-1]
   }
   
   for j := 0; j < n-1; j++ {
     varName := a.ColNames[j]
     if _, exists := solution[varName]; !exists {
-      solution[varName] = fr.Fraction{N: 0, D: 1}
+      var zero T
+      solution[varName] = zero
     }
   }
+
+  // Complemented variables hold u - x
+  for varName := range a.flipped {
+    solution[varName] = a.upper[varName].Sub(solution[varName])
+  }
+
+  values := make(map[string]T, len(a.recover))
+  for varName, rec := range a.recover {
+    value := rec.constant
+    for v, c := range rec.terms {
+      value = value.Add(c.Mul(solution[v]))
+    }
+    values[varName] = value
+  }
+  for _, rec := range a.recover {
+    for v := range rec.terms {
+      delete(solution, v) // Stands in for an original variable
+    }
+  }
+  for varName, value := range values {
+    solution[varName] = value
+  }
   
   return solution
 }
 
 // Check if all RHS values are non-negative (feasible solution)
-func (a *Tableau) IsFeasible() bool {
+func (a *Tableau[T]) IsFeasible() bool {
   n := len(a.Table[0])
   
-  for i := 0; i < len(a.Table)-1; i++ {
-    if a.Table[i][n-1].N < 0 {
+  for i := 0; i < a.rows(); i++ {
+    if a.Table[i][n-1].Sign() < 0 {
       return false
     }
   }
@@ -322,13 +291,12 @@ func (a *Tableau) IsFeasible() bool {
 }
 
 // Check if optimal solution is reached
-func (a *Tableau) IsOptimal() bool {
+func (a *Tableau[T]) IsOptimal() bool {
   n := len(a.Table[0]) // Number of columns
-  m := len(a.Table)    // Number of rows
   
   for j := 0; j < n-1; j++ {
-    if (a.IsMaximization && a.Table[m-1][j].N < 0) || 
-       (!a.IsMaximization && a.Table[m-1][j].N > 0) {
+    if (a.maximizing() && a.cost(j).Sign() < 0) || 
+       (!a.maximizing() && a.cost(j).Sign() > 0) {
       return false
     }
   }
@@ -336,6 +304,30 @@ func (a *Tableau) IsOptimal() bool {
   return true
 }
 
-func (a *Tableau) SetMaximization(isMax bool) {
+func (a *Tableau[T]) SetMaximization(isMax bool) {
   a.IsMaximization = isMax
 }
+
+// rows returns the number of constraint rows; the objective rows follow them
+func (a *Tableau[T]) rows() int {
+  if a.phaseOne || a.bigM {
+    return len(a.Table) - 2
+  }
+  return len(a.Table) - 1
+}
+
+// cost returns the entry of the objective row in column j, with its part in
+// M when the tableau uses the Big-M method
+func (a *Tableau[T]) cost(j int) BigM[T] {
+  m := len(a.Table)
+  if a.bigM {
+    return BigM[T]{M: a.Table[m-1][j], C: a.Table[m-2][j]}
+  }
+  return BigM[T]{C: a.Table[m-1][j]}
+}
+
+// maximizing reports the direction of the objective in the last row. The
+// Phase I objective W is always maximised
+func (a *Tableau[T]) maximizing() bool {
+  return a.IsMaximization || a.phaseOne
+}
@@ -0,0 +1,234 @@
+package tableau
+
+import (
+  "encoding/json"
+  "fmt"
+  "io"
+  "strings"
+)
+
+// Value is an entry of a Snapshot: a number of the tableau, or a BigM
+type Value interface {
+  Sign() int
+  fmt.Stringer
+  fmt.Formatter
+}
+
+// Snapshot is a copy of a tableau as Print shows it, independent of the
+// number type: names carry the "'" of complemented variables, and in the
+// Big-M method the objective row holds BigM values and the M row is left
+// out
+type Snapshot struct {
+  RowNames []string // Basic variables, then the objective rows
+  ColNames []string // Nonbasic variables, then "const"
+  Table [][]Value
+  Basis Basis
+}
+
+// Snapshot returns a copy of the current state of t
+func (t *Tableau[T]) Snapshot() *Snapshot {
+  s := &Snapshot{Basis: t.Basis()}
+  for _, v := range t.ColNames {
+    s.ColNames = append(s.ColNames, t.display(v))
+  }
+  for i := range t.Table {
+    row := make([]Value, len(t.Table[i]))
+    for j, x := range t.Table[i] {
+      if t.bigM && i == t.rows() {
+        row[j] = t.cost(j) // F with its part in M
+      } else {
+        row[j] = x
+      }
+    }
+    s.RowNames = append(s.RowNames, t.display(t.RowNames[i]))
+    s.Table = append(s.Table, row)
+    if t.bigM && i == t.rows() {
+      break
+    }
+  }
+  return s
+}
+
+// Fprint writes s to w as a table, with the basic variables down the left
+// and the nonbasic ones, negated, across the top
+func (s *Snapshot) Fprint(w io.Writer) {
+  fmt.Fprintln(w, "Current Tableau:")
+  
+  fmt.Fprintf(w, "%-10s", "")
+  for j, name := range s.ColNames {
+    if j < len(s.ColNames) - 1 {
+      fmt.Fprintf(w, "%-10s", "-" + name)
+    } else {
+      fmt.Fprintf(w, "%-10s", name)
+    }
+  }
+  fmt.Fprintln(w)
+  
+  for i, row := range s.Table {
+    fmt.Fprintf(w, "%-10s", s.RowNames[i])
+    for _, x := range row {
+      fmt.Fprintf(w, "% -10v", x)
+    }
+    fmt.Fprintln(w)
+  }
+}
+
+// MarshalJSON writes s with its entries as strings, "3/4" or "2M - 3"
+func (s *Snapshot) MarshalJSON() ([]byte, error) {
+  table := make([][]string, len(s.Table))
+  for i, row := range s.Table {
+    table[i] = make([]string, len(row))
+    for j, x := range row {
+      table[i][j] = x.String()
+    }
+  }
+  return json.Marshal(struct {
+    RowNames []string `json:"rows"`
+    ColNames []string `json:"cols"`
+    Table [][]string `json:"table"`
+    Basis Basis `json:"basis"`
+  }{s.RowNames, s.ColNames, table, s.Basis})
+}
+
+func (s *Snapshot) String() string {
+  var b strings.Builder
+  s.Fprint(&b)
+  return b.String()
+}
+
+// Step is a pivot of a simplex run
+type Step struct {
+  Phase string // "Phase I", "Big-M", "Phase II" or "Dual"
+  Iteration int // Counted from 1 in the phase, or over the run by simplex.Solve
+  Row, Col int // Pivot element, Row -1 for a bound flip of column Col, or both -1 for a phase change in a History
+  Entering, Leaving string // Leaving is empty for a bound flip, and both for a phase change
+}
+
+// Tracer observes a simplex run. The drivers of a tableau report to its
+// Tracer field, and simplex.Solve to Options.Tracer. Each event carries a
+// snapshot of the tableau at that point.
+type Tracer interface {
+  // OnInit reports the tableau a run starts from
+  OnInit(t *Snapshot)
+  // OnPhaseChange reports the start of a phase, named as in Step
+  OnPhaseChange(phase string, t *Snapshot)
+  // OnPivotChosen reports a pivot before the exchange
+  OnPivotChosen(step Step, t *Snapshot)
+  // OnTransform reports the tableau after the exchange of step
+  OnTransform(step Step, t *Snapshot)
+  // OnCycle reports a basis that repeated, after which the run continues
+  // with Bland's rule
+  OnCycle(c Cycle, t *Snapshot)
+  // OnTerminate reports the end of a run, with the error that matches
+  // status unless it is Optimal, or the cause of the failure for Failed
+  OnTerminate(status Status, err error, t *Snapshot)
+}
+
+// emit calls f with the tracer and a snapshot of t, if t has a tracer
+func (t *Tableau[T]) emit(f func(tr Tracer, s *Snapshot)) {
+  if t.Tracer != nil {
+    f(t.Tracer, t.Snapshot())
+  }
+}
+
+// NopTracer ignores every event
+type NopTracer struct{}
+
+func (NopTracer) OnInit(*Snapshot) {}
+func (NopTracer) OnPhaseChange(string, *Snapshot) {}
+func (NopTracer) OnPivotChosen(Step, *Snapshot) {}
+func (NopTracer) OnTransform(Step, *Snapshot) {}
+func (NopTracer) OnCycle(Cycle, *Snapshot) {}
+func (NopTracer) OnTerminate(Status, error, *Snapshot) {}
+
+// ConsoleTracer prints a run the way the command always has: each pivot
+// with the tableau that follows it
+type ConsoleTracer struct {
+  W io.Writer
+}
+
+func (c ConsoleTracer) OnInit(t *Snapshot) {
+  fmt.Fprintln(c.W, "\nInitial Tableau:")
+  t.Fprint(c.W)
+}
+
+func (c ConsoleTracer) OnPhaseChange(phase string, t *Snapshot) {
+  switch phase {
+  case "Phase I":
+    fmt.Fprintln(c.W, "\nPhase I: maximising W = -(sum of artificial variables)")
+  case "Big-M":
+    fmt.Fprintln(c.W, "\nBig-M: pivoting artificial variables out of the basis")
+  case "Dual":
+    fmt.Fprintln(c.W, "\nDual simplex: restoring feasibility")
+  default:
+    fmt.Fprintf(c.W, "\n%s\n", phase)
+  }
+}
+
+func (c ConsoleTracer) OnPivotChosen(step Step, t *Snapshot) {
+  fmt.Fprintf(c.W, "\n--- %s Iteration %d ---\n", step.Phase, step.Iteration)
+  if step.Row < 0 {
+    fmt.Fprintf(c.W, "Bound flip on column %d: %s moves to its other bound\n", step.Col, step.Entering)
+    return
+  }
+  fmt.Fprintf(c.W, "Pivoting on element at row %d, column %d (intersection of %s and %s)\n",
+    step.Row, step.Col, step.Leaving, step.Entering)
+  if step.Phase != "Dual" && t.Table[step.Row][step.Col].Sign() < 0 {
+    fmt.Fprintf(c.W, "%s leaves at its upper bound, so its row is complemented first\n", step.Leaving)
+  }
+}
+
+func (c ConsoleTracer) OnTransform(step Step, t *Snapshot) {
+  t.Fprint(c.W)
+}
+
+func (c ConsoleTracer) OnCycle(cycle Cycle, t *Snapshot) {
+  fmt.Fprintln(c.W, "Cycling detected:", cycle)
+  fmt.Fprintln(c.W, "Switching to Bland's rule")
+}
+
+func (c ConsoleTracer) OnTerminate(status Status, err error, t *Snapshot) {
+  fmt.Fprintln(c.W, "\nFinal Tableau:")
+  t.Fprint(c.W)
+  fmt.Fprintln(c.W, "\nStatus:", status)
+}
+
+// Event is a Tracer event kept by a Recorder
+type Event struct {
+  Kind string // "init", "phase", "pivot", "transform", "cycle" or "terminate"
+  Phase string // Of "phase" events
+  Step *Step // Of "pivot" and "transform" events
+  Cycle *Cycle // Of "cycle" events
+  Status Status // Of "terminate" events
+  Err error // Of "terminate" events
+  Tableau *Snapshot
+}
+
+// Recorder keeps every event in order
+type Recorder struct {
+  Events []Event
+}
+
+func (r *Recorder) OnInit(t *Snapshot) {
+  r.Events = append(r.Events, Event{Kind: "init", Tableau: t})
+}
+
+func (r *Recorder) OnPhaseChange(phase string, t *Snapshot) {
+  r.Events = append(r.Events, Event{Kind: "phase", Phase: phase, Tableau: t})
+}
+
+func (r *Recorder) OnPivotChosen(step Step, t *Snapshot) {
+  r.Events = append(r.Events, Event{Kind: "pivot", Step: &step, Tableau: t})
+}
+
+func (r *Recorder) OnTransform(step Step, t *Snapshot) {
+  r.Events = append(r.Events, Event{Kind: "transform", Step: &step, Tableau: t})
+}
+
+func (r *Recorder) OnCycle(c Cycle, t *Snapshot) {
+  r.Events = append(r.Events, Event{Kind: "cycle", Cycle: &c, Tableau: t})
+}
+
+func (r *Recorder) OnTerminate(status Status, err error, t *Snapshot) {
+  r.Events = append(r.Events, Event{Kind: "terminate", Status: status, Err: err, Tableau: t})
+}